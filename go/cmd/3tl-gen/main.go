@@ -0,0 +1,62 @@
+// Command 3tl-gen reads a .3tl file and emits Go struct declarations for its schema.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jiriknesl/3tl/pkg/codegen"
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+func main() {
+	pkgFlag := flag.String("package", "model", "Go package name for the generated file")
+	outFlag := flag.String("out", "", "Output file (defaults to stdout)")
+	constructorsFlag := flag.Bool("constructors", false, "Generate New<Table>(...) constructors")
+	loadersFlag := flag.Bool("loaders", true, "Generate Load<Table>s(doc) loader helpers")
+	typedRefsFlag := flag.Bool("typed-refs", false, "Generate typed IDs for ref(...) columns instead of pointer fields")
+	decimalTypeFlag := flag.Bool("decimal-type", false, "Map decimal(p,s) columns to decimal.Decimal instead of a validated string")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] <file.3tl>\n", os.Args[0])
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+
+	doc, err := parser.ParseFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing file: %v\n", err)
+		os.Exit(1)
+	}
+
+	src, err := codegen.Generate(doc, codegen.Options{
+		Package:        *pkgFlag,
+		Constructors:   *constructorsFlag,
+		Loaders:        *loadersFlag,
+		RefAsTypedID:   *typedRefsFlag,
+		UseDecimalType: *decimalTypeFlag,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating code: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if *outFlag != "" {
+		f, err := os.Create(*outFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if _, err := out.Write(src); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -5,33 +5,61 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/jiriknesl/3tl/pkg/emit"
 	"github.com/jiriknesl/3tl/pkg/parser"
 )
 
+var sqlDialects = map[string]emit.Dialect{
+	"postgres": emit.Postgres,
+	"mysql":    emit.MySQL,
+	"sqlite":   emit.SQLite,
+}
+
 func main() {
 	prettyFlag := flag.Bool("pretty", false, "Pretty-print JSON output")
+	formatFlag := flag.String("format", "json", "Output format: json, csv, sql, or parquet")
+	dialectFlag := flag.String("dialect", "postgres", "SQL dialect when --format=sql: postgres, mysql, or sqlite")
 	flag.Parse()
 
 	args := flag.Args()
 	if len(args) != 1 {
-		fmt.Fprintf(os.Stderr, "Usage: %s [--pretty] <file.3tl>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [--format=json|csv|sql|parquet] [--pretty] [--dialect=postgres|mysql|sqlite] <file.3tl>\n", os.Args[0])
 		os.Exit(1)
 	}
 
 	filename := args[0]
 
-	// Parse the file
 	doc, err := parser.ParseFile(filename)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing file: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Output JSON
-	if err := parser.WriteJSON(os.Stdout, doc, *prettyFlag); err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing JSON: %v\n", err)
+	format := emit.Format(*formatFlag)
+	if format == emit.FormatSQL {
+		dialect, ok := sqlDialects[*dialectFlag]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown SQL dialect %q\n", *dialectFlag)
+			os.Exit(1)
+		}
+		emit.Register(emit.FormatSQL, emit.NewSQL(dialect))
+	}
+	if format == emit.FormatJSON {
+		emit.Register(emit.FormatJSON, emit.NewJSON(*prettyFlag))
+	}
+
+	emitter, err := emit.For(format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := emitter.Emit(os.Stdout, doc); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println() // Add newline at end
+	if format == emit.FormatJSON {
+		fmt.Println() // Add newline at end
+	}
 }
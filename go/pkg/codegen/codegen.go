@@ -0,0 +1,256 @@
+// Package codegen generates idiomatic Go type declarations from a 3TL schema, turning
+// 3TL into a first-class schema-driven code source: one struct per table, a Validate
+// method enforcing the declared constraints, and (optionally) loader helpers that
+// decode a *parser.Document into typed slices.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+// Options controls how Generate renders Go source from a 3TL schema.
+type Options struct {
+	// Package is the generated file's package name. Defaults to "model".
+	Package string
+	// Constructors emits a New<Table>(...) function per struct.
+	Constructors bool
+	// Loaders emits a Load<Table>s(doc *parser.Document) ([]<Table>, error) helper
+	// per table that decodes and validates every row.
+	Loaders bool
+	// RefAsTypedID makes ref(T.c) columns a generated <T>ID named type instead of a
+	// *<T> pointer field.
+	RefAsTypedID bool
+	// UseDecimalType makes decimal(p,s) columns a decimal.Decimal (requiring
+	// github.com/shopspring/decimal) instead of a validated string.
+	UseDecimalType bool
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Package == "" {
+		opts.Package = "model"
+	}
+	return opts
+}
+
+type fieldSpec struct {
+	ColumnName   string
+	GoName       string
+	GoType       string // full declared field type, e.g. "*int32", "[]string", "OrderStatus"
+	ScalarGoType string // GoType without its "[]" or nullable "*" wrapper (a pointer ref keeps its "*"); used for value conversion
+	IsArray      bool
+	IsNullable   bool
+	IsRef        bool
+	RefIsPointer bool
+	EnumTypeName string
+	EnumValues   []string
+	IsDecimalStr bool
+	DecimalP     int
+	DecimalS     int
+}
+
+type structSpec struct {
+	TableName string
+	GoName    string
+	Fields    []fieldSpec
+}
+
+var scalarGoTypes = map[string]string{
+	"i8": "int8", "i16": "int16", "i32": "int32", "i64": "int64", "int": "int",
+	"u8": "uint8", "u16": "uint16", "u32": "uint32", "u64": "uint64", "uint": "uint",
+	"f32": "float32", "f64": "float64", "float": "float64",
+	"bool": "bool",
+	"str":  "string", "text": "string",
+}
+
+var timeKinds = map[string]bool{"date": true, "time": true, "datetime": true, "timestamp": true}
+
+// Generate renders Go source declaring one struct per table in doc, formatted with
+// go/format. The caller is responsible for writing the result to a file.
+func Generate(doc *parser.Document, opts Options) ([]byte, error) {
+	opts = withDefaults(opts)
+
+	structs := make([]structSpec, len(doc.Tables))
+	needsTime := false
+	needsDecimalPkg := false
+	needsDecimalHelper := false
+	refIDTypes := map[string]bool{}
+	for i := range doc.Tables {
+		spec, usesTime, usesDecimalPkg := buildStruct(&doc.Tables[i], opts)
+		structs[i] = spec
+		needsTime = needsTime || usesTime
+		needsDecimalPkg = needsDecimalPkg || usesDecimalPkg
+		for _, f := range spec.Fields {
+			needsDecimalHelper = needsDecimalHelper || f.IsDecimalStr
+			if f.IsRef && !f.RefIsPointer {
+				refIDTypes[f.ScalarGoType] = true
+			}
+		}
+	}
+	// The decode helpers emitted for opts.Loaders always include a time.Time-typed
+	// helper, so "time" must be imported whenever they are present.
+	needsTime = needsTime || opts.Loaders
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by 3tl-gen. DO NOT EDIT.\npackage %s\n\n", opts.Package)
+
+	writeImports(&buf, opts, needsTime, needsDecimalPkg, needsDecimalHelper)
+
+	if needsDecimalHelper {
+		writeDecimalValidator(&buf)
+	}
+	if opts.Loaders {
+		writeDecodeHelpers(&buf)
+	}
+	writeRefIDTypes(&buf, refIDTypes)
+
+	for _, s := range structs {
+		writeEnumTypes(&buf, s)
+		writeStructDecl(&buf, s)
+		writeValidateMethod(&buf, s)
+		if opts.Constructors {
+			writeConstructor(&buf, s)
+		}
+		if opts.Loaders {
+			writeLoader(&buf, s)
+		}
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("format generated source: %w", err)
+	}
+	return formatted, nil
+}
+
+func writeImports(buf *bytes.Buffer, opts Options, needsTime, needsDecimalPkg, needsDecimalHelper bool) {
+	var lines []string
+	lines = append(lines, `"fmt"`)
+	if opts.Loaders {
+		lines = append(lines, `"strconv"`)
+	}
+	if opts.Loaders || needsDecimalHelper {
+		lines = append(lines, `"strings"`)
+	}
+	if needsTime {
+		lines = append(lines, `"time"`)
+	}
+	if needsDecimalPkg {
+		lines = append(lines, `"github.com/shopspring/decimal"`)
+	}
+	if opts.Loaders {
+		lines = append(lines, `"github.com/jiriknesl/3tl/pkg/parser"`)
+	}
+
+	if len(lines) == 1 {
+		fmt.Fprintf(buf, "import %s\n\n", lines[0])
+		return
+	}
+	buf.WriteString("import (\n")
+	for _, l := range lines {
+		fmt.Fprintf(buf, "\t%s\n", l)
+	}
+	buf.WriteString(")\n\n")
+}
+
+func buildStruct(table *parser.Table, opts Options) (structSpec, bool, bool) {
+	spec := structSpec{TableName: table.Name, GoName: toGoIdent(table.Name)}
+	needsTime := false
+	needsDecimalPkg := false
+
+	for _, col := range table.Columns {
+		ts := parser.ParseTypeSpec(col.Type)
+		f := fieldSpec{ColumnName: col.Name, GoName: toGoFieldName(col.Name), IsArray: ts.Array, IsNullable: ts.Nullable}
+
+		switch {
+		case ts.Kind == "decimal":
+			if opts.UseDecimalType {
+				f.ScalarGoType = "decimal.Decimal"
+				needsDecimalPkg = true
+			} else {
+				f.ScalarGoType = "string"
+				f.IsDecimalStr = true
+				if len(ts.Params) >= 2 {
+					f.DecimalP, _ = strconv.Atoi(ts.Params[0])
+					f.DecimalS, _ = strconv.Atoi(ts.Params[1])
+				}
+			}
+
+		case ts.Kind == "enum":
+			f.EnumTypeName = spec.GoName + f.GoName
+			f.EnumValues = ts.Params
+			f.ScalarGoType = f.EnumTypeName
+
+		case ts.Kind == "ref":
+			f.IsRef = true
+			refTable := "Ref"
+			if len(ts.Params) >= 1 {
+				refTable = ts.Params[0]
+			}
+			if opts.RefAsTypedID {
+				f.ScalarGoType = toGoIdent(refTable) + "ID"
+			} else {
+				f.RefIsPointer = true
+				f.ScalarGoType = "*" + toGoIdent(refTable)
+			}
+
+		case timeKinds[ts.Kind]:
+			f.ScalarGoType = "time.Time"
+			needsTime = true
+
+		default:
+			if gt, ok := scalarGoTypes[ts.Kind]; ok {
+				f.ScalarGoType = gt
+			} else {
+				f.ScalarGoType = "any"
+			}
+		}
+
+		f.GoType = f.ScalarGoType
+		if f.IsArray {
+			f.GoType = "[]" + f.GoType
+		} else if f.IsNullable && !strings.HasPrefix(f.GoType, "*") {
+			f.GoType = "*" + f.GoType
+		}
+
+		spec.Fields = append(spec.Fields, f)
+	}
+
+	return spec, needsTime, needsDecimalPkg
+}
+
+func toGoFieldName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	var b strings.Builder
+	for _, p := range parts {
+		r := []rune(p)
+		if len(r) == 0 {
+			continue
+		}
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	if b.Len() == 0 {
+		return "Field"
+	}
+	return b.String()
+}
+
+func toGoIdent(name string) string {
+	return toGoFieldName(name)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,263 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// writeRefIDTypes declares every distinct "<Table>ID" typed-ID type used by ref(T.c)
+// columns when Options.RefAsTypedID is set. Each is a plain string wrapper around the
+// referenced key, with no validation of its own, since the generator does not know the referenced column's Go
+// type without resolving cross-table schema at generation time.
+func writeRefIDTypes(buf *bytes.Buffer, refIDTypes map[string]bool) {
+	names := make([]string, 0, len(refIDTypes))
+	for name := range refIDTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(buf, "// %s is a typed reference key generated for a ref(...) column.\ntype %s string\n\n", name, name)
+	}
+}
+
+func writeEnumTypes(buf *bytes.Buffer, s structSpec) {
+	for _, f := range s.Fields {
+		if f.EnumTypeName == "" {
+			continue
+		}
+
+		fmt.Fprintf(buf, "// %s is the generated enum type for %s.%s.\n", f.EnumTypeName, s.TableName, f.ColumnName)
+		fmt.Fprintf(buf, "type %s string\n\n", f.EnumTypeName)
+
+		buf.WriteString("const (\n")
+		for _, v := range f.EnumValues {
+			fmt.Fprintf(buf, "\t%s%s %s = %q\n", f.EnumTypeName, toGoFieldName(v), f.EnumTypeName, v)
+		}
+		buf.WriteString(")\n\n")
+	}
+}
+
+func writeStructDecl(buf *bytes.Buffer, s structSpec) {
+	fmt.Fprintf(buf, "// %s maps the 3TL %q table.\ntype %s struct {\n", s.GoName, s.TableName, s.GoName)
+	for _, f := range s.Fields {
+		fmt.Fprintf(buf, "\t%s %s `json:%q db:%q`\n", f.GoName, f.GoType, f.ColumnName, f.ColumnName)
+	}
+	buf.WriteString("}\n\n")
+}
+
+func writeValidateMethod(buf *bytes.Buffer, s structSpec) {
+	fmt.Fprintf(buf, "// Validate checks v against the constraints declared in the 3TL schema %s was generated from.\n", s.GoName)
+	fmt.Fprintf(buf, "func (v *%s) Validate() error {\n", s.GoName)
+
+	for _, f := range s.Fields {
+		if f.EnumTypeName == "" && !f.IsDecimalStr {
+			continue
+		}
+		target := "v." + f.GoName
+		switch {
+		case f.IsArray:
+			fmt.Fprintf(buf, "\tfor _, x := range v.%s {\n", f.GoName)
+			target = "x"
+		case f.IsNullable:
+			fmt.Fprintf(buf, "\tif v.%s != nil {\n", f.GoName)
+			target = "*v." + f.GoName
+		}
+		if f.EnumTypeName != "" {
+			fmt.Fprintf(buf, "\tswitch %s {\n\tcase ", target)
+			for i, val := range f.EnumValues {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				fmt.Fprintf(buf, "%s%s", f.EnumTypeName, toGoFieldName(val))
+			}
+			buf.WriteString(":\n")
+			fmt.Fprintf(buf, "\tdefault:\n\t\treturn fmt.Errorf(\"%s.%s: invalid value %%q\", %s)\n\t}\n", s.GoName, f.GoName, target)
+		}
+		if f.IsDecimalStr {
+			fmt.Fprintf(buf, "\tif err := validateDecimalString(%s, %d, %d); err != nil {\n", target, f.DecimalP, f.DecimalS)
+			fmt.Fprintf(buf, "\t\treturn fmt.Errorf(\"%s.%s: %%w\", err)\n\t}\n", s.GoName, f.GoName)
+		}
+		if f.IsArray || f.IsNullable {
+			buf.WriteString("\t}\n")
+		}
+	}
+
+	buf.WriteString("\treturn nil\n}\n\n")
+}
+
+func writeConstructor(buf *bytes.Buffer, s structSpec) {
+	fmt.Fprintf(buf, "// New%s constructs a %s from its fields.\n", s.GoName, s.GoName)
+	fmt.Fprintf(buf, "func New%s(", s.GoName)
+	for i, f := range s.Fields {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(buf, "%s %s", lowerFirst(f.GoName), f.GoType)
+	}
+	fmt.Fprintf(buf, ") *%s {\n\treturn &%s{\n", s.GoName, s.GoName)
+	for _, f := range s.Fields {
+		fmt.Fprintf(buf, "\t\t%s: %s,\n", f.GoName, lowerFirst(f.GoName))
+	}
+	buf.WriteString("\t}\n}\n\n")
+}
+
+func writeLoader(buf *bytes.Buffer, s structSpec) {
+	fmt.Fprintf(buf, "// Load%ss decodes every row of the %q table in doc into a []%s, validating each row as it is built.\n", s.GoName, s.TableName, s.GoName)
+	fmt.Fprintf(buf, "func Load%ss(doc *parser.Document) ([]%s, error) {\n", s.GoName, s.GoName)
+	fmt.Fprintf(buf, "\tvar table *parser.Table\n\tfor i := range doc.Tables {\n\t\tif doc.Tables[i].Name == %q {\n\t\t\ttable = &doc.Tables[i]\n\t\t\tbreak\n\t\t}\n\t}\n", s.TableName)
+	fmt.Fprintf(buf, "\tif table == nil {\n\t\treturn nil, fmt.Errorf(\"table %%q not found\", %q)\n\t}\n\n", s.TableName)
+
+	buf.WriteString("\tcolIdx := make(map[string]int, len(table.Columns))\n")
+	buf.WriteString("\tfor i, c := range table.Columns {\n\t\tcolIdx[c.Name] = i\n\t}\n\n")
+
+	fmt.Fprintf(buf, "\tout := make([]%s, 0, len(table.Rows))\n", s.GoName)
+	fmt.Fprintf(buf, "\tfor ri, row := range table.Rows {\n\t\tvar item %s\n", s.GoName)
+
+	for _, f := range s.Fields {
+		writeFieldAssignment(buf, f)
+	}
+
+	fmt.Fprintf(buf, "\t\tif err := item.Validate(); err != nil {\n\t\t\treturn nil, fmt.Errorf(\"row %%d: %%w\", ri, err)\n\t\t}\n")
+	buf.WriteString("\t\tout = append(out, item)\n\t}\n\n\treturn out, nil\n}\n\n")
+}
+
+func writeFieldAssignment(buf *bytes.Buffer, f fieldSpec) {
+	valueExpr := fmt.Sprintf("row[colIdx[%q]]", f.ColumnName)
+
+	if f.IsRef && f.RefIsPointer {
+		fmt.Fprintf(buf, "\t\t// %s (column %q) is a reference column; loaders do not resolve it automatically.\n", f.GoName, f.ColumnName)
+		return
+	}
+
+	switch {
+	case f.IsArray:
+		fmt.Fprintf(buf, "\t\tfor _, raw := range splitArrayField(%s) {\n", valueExpr)
+		fmt.Fprintf(buf, "\t\t\titem.%s = append(item.%s, %s)\n", f.GoName, f.GoName, convertScalarExpr(f.ScalarGoType, "raw"))
+		buf.WriteString("\t\t}\n")
+
+	case f.IsNullable:
+		fmt.Fprintf(buf, "\t\tif %s != nil {\n", valueExpr)
+		fmt.Fprintf(buf, "\t\t\tval := %s\n", convertScalarExpr(f.ScalarGoType, valueExpr))
+		fmt.Fprintf(buf, "\t\t\titem.%s = &val\n\t\t}\n", f.GoName)
+
+	default:
+		fmt.Fprintf(buf, "\t\titem.%s = %s\n", f.GoName, convertScalarExpr(f.ScalarGoType, valueExpr))
+	}
+}
+
+func convertScalarExpr(goType, valueExpr string) string {
+	switch goType {
+	case "int8", "int16", "int32", "int64", "int", "uint8", "uint16", "uint32", "uint64", "uint":
+		return fmt.Sprintf("%s(toInt64Field(%s))", goType, valueExpr)
+	case "float32", "float64":
+		return fmt.Sprintf("%s(toFloat64Field(%s))", goType, valueExpr)
+	case "bool":
+		return fmt.Sprintf("toBoolField(%s)", valueExpr)
+	case "string":
+		return fmt.Sprintf("toStringField(%s)", valueExpr)
+	case "time.Time":
+		return fmt.Sprintf("toTimeField(%s)", valueExpr)
+	case "decimal.Decimal":
+		return fmt.Sprintf("decimal.RequireFromString(toStringField(%s))", valueExpr)
+	default:
+		// Generated enum types (and anything else) are string-based named types.
+		return fmt.Sprintf("%s(toStringField(%s))", goType, valueExpr)
+	}
+}
+
+func writeDecimalValidator(buf *bytes.Buffer) {
+	buf.WriteString(`// validateDecimalString checks that s fits within precision total digits and at
+// most scale digits after the decimal point.
+func validateDecimalString(s string, precision, scale int) error {
+	s = strings.TrimPrefix(s, "-")
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	if len(fracPart) > scale {
+		return fmt.Errorf("value exceeds scale %d", scale)
+	}
+
+	digits := len(strings.TrimLeft(intPart, "0")) + len(fracPart)
+	if digits == 0 {
+		digits = 1
+	}
+	if digits > precision {
+		return fmt.Errorf("value exceeds precision %d", precision)
+	}
+
+	return nil
+}
+
+`)
+}
+
+func writeDecodeHelpers(buf *bytes.Buffer) {
+	buf.WriteString(`func toInt64Field(v any) int64 {
+	switch n := v.(type) {
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	case string:
+		if parsed, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return 0
+}
+
+func toFloat64Field(v any) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case string:
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+			return parsed
+		}
+	}
+	return 0
+}
+
+func toBoolField(v any) bool {
+	switch n := v.(type) {
+	case bool:
+		return n
+	case string:
+		return strings.EqualFold(strings.TrimSpace(n), "true")
+	}
+	return false
+}
+
+func toStringField(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func toTimeField(v any) time.Time {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}
+	}
+	for _, layout := range parser.TimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func splitArrayField(v any) []string {
+	raw, err := parser.SplitArray(v)
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+`)
+}
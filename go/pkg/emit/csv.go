@@ -0,0 +1,75 @@
+package emit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+// csvEmitter renders each table as its own CSV block: a "# TableName" comment, a header
+// row of column names, then one row per record. Blocks are separated by a blank line so
+// a multi-table Document still round-trips through a single file.
+type csvEmitter struct{}
+
+// NewCSV returns an Emitter that writes doc as CSV, one block per table.
+func NewCSV() Emitter {
+	return csvEmitter{}
+}
+
+func (csvEmitter) Emit(w io.Writer, doc *parser.Document) error {
+	for i, table := range doc.Tables {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "# %s\n", table.Name); err != nil {
+			return err
+		}
+
+		cw := csv.NewWriter(w)
+		header := make([]string, len(table.Columns))
+		for j, col := range table.Columns {
+			header[j] = col.Name
+		}
+		if err := cw.Write(header); err != nil {
+			return fmt.Errorf("emit csv: table %s: %w", table.Name, err)
+		}
+
+		for _, row := range table.Rows {
+			record := make([]string, len(row))
+			for j, v := range row {
+				record[j] = csvFieldString(v)
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("emit csv: table %s: %w", table.Name, err)
+			}
+		}
+
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return fmt.Errorf("emit csv: table %s: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+func csvFieldString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case []any:
+		parts := make([]string, len(x))
+		for i, e := range x {
+			parts[i] = csvFieldString(e)
+		}
+		return strings.Join(parts, "|")
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
@@ -0,0 +1,221 @@
+package emit
+
+import (
+	"strings"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+// Dialect spells out the identifier quoting, type names, and bulk-load syntax that
+// differ across SQL engines. Postgres, MySQL, and SQLite are provided; a caller can
+// build its own for anything else and pass it to NewSQL.
+type Dialect struct {
+	Name string
+
+	// QuoteIdent quotes a table or column name for safe use as an identifier.
+	QuoteIdent func(name string) string
+
+	// ColumnType renders the base SQL type for a 3TL column (no NOT NULL, CHECK, or
+	// array wrapping - the emitter adds those). doc is passed through so ref(T.c)
+	// columns can be resolved to the type of the column they reference.
+	ColumnType func(doc *parser.Document, ts typeSpec) string
+
+	// WrapArray adapts a base type for a 3TL array column. Only Postgres has native
+	// array types; the other dialects fall back to a delimited TEXT column, matching
+	// how csvEmitter and the 3TL text format itself represent arrays.
+	WrapArray func(base string) string
+
+	// UseCopy emits a COPY ... FROM STDIN block instead of INSERT statements for bulk
+	// loading rows. Only Postgres supports COPY in this package.
+	UseCopy bool
+
+	// NativeArray is true when the dialect has a real array type, so array values are
+	// rendered as array literals instead of the WrapArray fallback's delimited string.
+	NativeArray bool
+}
+
+func quoteDouble(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteBacktick(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Postgres spells integers INT2/INT4/INT8, supports native array types, and bulk-loads
+// rows via COPY FROM STDIN.
+var Postgres = Dialect{
+	Name:        "postgres",
+	QuoteIdent:  quoteDouble,
+	ColumnType:  postgresColumnType,
+	WrapArray:   func(base string) string { return base + "[]" },
+	UseCopy:     true,
+	NativeArray: true,
+}
+
+// MySQL spells integers TINYINT/SMALLINT/INT/BIGINT (UNSIGNED for 3TL's unsigned
+// kinds), uses backtick-quoted identifiers, and loads rows with one INSERT per row.
+var MySQL = Dialect{
+	Name:       "mysql",
+	QuoteIdent: quoteBacktick,
+	ColumnType: mysqlColumnType,
+	WrapArray:  func(string) string { return "TEXT" },
+	UseCopy:    false,
+}
+
+// SQLite only has column affinities, not real types, so every kind maps to whichever
+// affinity keyword best documents the intent. Identifiers are double-quoted, matching
+// the SQL standard that SQLite also accepts.
+var SQLite = Dialect{
+	Name:       "sqlite",
+	QuoteIdent: quoteDouble,
+	ColumnType: sqliteColumnType,
+	WrapArray:  func(string) string { return "TEXT" },
+	UseCopy:    false,
+}
+
+func resolveRef(doc *parser.Document, ts typeSpec) (typeSpec, bool) {
+	if len(ts.Params) < 2 {
+		return typeSpec{}, false
+	}
+	refTable, refCol := ts.Params[0], ts.Params[1]
+
+	for i := range doc.Tables {
+		if doc.Tables[i].Name != refTable {
+			continue
+		}
+		for _, c := range doc.Tables[i].Columns {
+			if c.Name == refCol {
+				return parseType(c.Type), true
+			}
+		}
+	}
+	return typeSpec{}, false
+}
+
+func postgresColumnType(doc *parser.Document, ts typeSpec) string {
+	if ts.Kind == "ref" {
+		if target, ok := resolveRef(doc, ts); ok {
+			return postgresColumnType(doc, target)
+		}
+		return "INT8"
+	}
+
+	switch ts.Kind {
+	case "i8", "i16":
+		return "INT2"
+	case "i32":
+		return "INT4"
+	case "i64", "int":
+		return "INT8"
+	case "u8", "u16", "u32":
+		return "INT8" // Postgres has no unsigned integer type; widen to avoid truncation.
+	case "u64", "uint":
+		return "NUMERIC(20,0)" // INT8 can't hold the full uint64 range.
+	case "f32":
+		return "REAL"
+	case "f64", "float":
+		return "DOUBLE PRECISION"
+	case "bool":
+		return "BOOLEAN"
+	case "str", "text":
+		return "TEXT"
+	case "date":
+		return "DATE"
+	case "time":
+		return "TIME"
+	case "datetime", "timestamp":
+		return "TIMESTAMP"
+	case "decimal":
+		return decimalType(ts, "NUMERIC")
+	case "enum":
+		return "TEXT"
+	default:
+		return "TEXT"
+	}
+}
+
+func mysqlColumnType(doc *parser.Document, ts typeSpec) string {
+	if ts.Kind == "ref" {
+		if target, ok := resolveRef(doc, ts); ok {
+			return mysqlColumnType(doc, target)
+		}
+		return "BIGINT"
+	}
+
+	switch ts.Kind {
+	case "i8":
+		return "TINYINT"
+	case "i16":
+		return "SMALLINT"
+	case "i32":
+		return "INT"
+	case "i64", "int":
+		return "BIGINT"
+	case "u8":
+		return "TINYINT UNSIGNED"
+	case "u16":
+		return "SMALLINT UNSIGNED"
+	case "u32":
+		return "INT UNSIGNED"
+	case "u64", "uint":
+		return "BIGINT UNSIGNED"
+	case "f32":
+		return "FLOAT"
+	case "f64", "float":
+		return "DOUBLE"
+	case "bool":
+		return "BOOLEAN"
+	case "str":
+		return "VARCHAR(255)"
+	case "text":
+		return "TEXT"
+	case "date":
+		return "DATE"
+	case "time":
+		return "TIME"
+	case "datetime":
+		return "DATETIME"
+	case "timestamp":
+		return "TIMESTAMP"
+	case "decimal":
+		return decimalType(ts, "DECIMAL")
+	case "enum":
+		return "VARCHAR(255)"
+	default:
+		return "TEXT"
+	}
+}
+
+func sqliteColumnType(doc *parser.Document, ts typeSpec) string {
+	if ts.Kind == "ref" {
+		if target, ok := resolveRef(doc, ts); ok {
+			return sqliteColumnType(doc, target)
+		}
+		return "INTEGER"
+	}
+
+	switch ts.Kind {
+	case "i8", "i16", "i32", "i64", "int", "u8", "u16", "u32", "u64", "uint":
+		return "INTEGER"
+	case "f32", "f64", "float":
+		return "REAL"
+	case "bool":
+		return "BOOLEAN"
+	case "str", "text", "enum":
+		return "TEXT"
+	case "date", "time", "datetime", "timestamp":
+		return "TEXT" // SQLite has no date/time type; ISO-8601 strings are the convention.
+	case "decimal":
+		return decimalType(ts, "NUMERIC")
+	default:
+		return "TEXT"
+	}
+}
+
+func decimalType(ts typeSpec, keyword string) string {
+	if len(ts.Params) >= 2 {
+		return keyword + "(" + ts.Params[0] + "," + ts.Params[1] + ")"
+	}
+	return keyword
+}
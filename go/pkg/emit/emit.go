@@ -0,0 +1,47 @@
+// Package emit renders a parsed 3TL Document into external output formats: JSON, CSV,
+// a SQL schema-plus-data script, and (once registered) anything else a caller defines.
+package emit
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+// Format names a registered output format, selected by a CLI's --format flag.
+type Format string
+
+const (
+	FormatJSON    Format = "json"
+	FormatCSV     Format = "csv"
+	FormatSQL     Format = "sql"
+	FormatParquet Format = "parquet"
+)
+
+// Emitter renders doc to w in some external format.
+type Emitter interface {
+	Emit(w io.Writer, doc *parser.Document) error
+}
+
+var registry = map[Format]Emitter{
+	FormatJSON:    NewJSON(false),
+	FormatCSV:     NewCSV(),
+	FormatSQL:     NewSQL(Postgres),
+	FormatParquet: NewParquet(),
+}
+
+// Register adds or replaces the Emitter used for format, letting a caller reconfigure a
+// built-in (e.g. SQL with a different Dialect) or register one of its own.
+func Register(format Format, e Emitter) {
+	registry[format] = e
+}
+
+// For returns the Emitter registered for format.
+func For(format Format) (Emitter, error) {
+	e, ok := registry[format]
+	if !ok {
+		return nil, fmt.Errorf("emit: unknown format %q", format)
+	}
+	return e, nil
+}
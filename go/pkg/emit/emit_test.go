@@ -0,0 +1,225 @@
+package emit
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+func mustDoc(t *testing.T, input string) *parser.Document {
+	t.Helper()
+	doc, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	return doc
+}
+
+func TestForUnknownFormat(t *testing.T) {
+	if _, err := For(Format("yaml")); err == nil {
+		t.Error("Expected an error for an unregistered format")
+	}
+}
+
+func TestForKnownFormats(t *testing.T) {
+	for _, f := range []Format{FormatJSON, FormatCSV, FormatSQL, FormatParquet} {
+		if _, err := For(f); err != nil {
+			t.Errorf("Expected %q to be registered, got error: %v", f, err)
+		}
+	}
+}
+
+func TestCSVEmitMultipleTables(t *testing.T) {
+	doc := mustDoc(t, `#! User
+#@ id:uint, name:str
+1, Alice
+
+#! Post
+#@ id:uint, title:str
+1, Hello
+`)
+
+	var buf bytes.Buffer
+	if err := NewCSV().Emit(&buf, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "# User\nid,name\n1,Alice\n") {
+		t.Errorf("Expected User CSV block, got:\n%s", out)
+	}
+	if !strings.Contains(out, "# Post\nid,title\n1,Hello\n") {
+		t.Errorf("Expected Post CSV block, got:\n%s", out)
+	}
+}
+
+func TestParquetEmitNotImplemented(t *testing.T) {
+	doc := mustDoc(t, "#! User\n#@ id:uint\n1\n")
+	var buf bytes.Buffer
+	if err := NewParquet().Emit(&buf, doc); err == nil {
+		t.Error("Expected parquet Emit to return an error")
+	}
+}
+
+func TestSQLCreateTableWithConstraints(t *testing.T) {
+	doc := mustDoc(t, `#! Article
+#@ id:uint, status:enum(draft | published), price:decimal(10,2)?
+1, draft, 9.99
+
+#! Comment
+#@ id:uint, article_id:ref(Article.id), body:str
+1, 1, Nice post
+`)
+
+	var buf bytes.Buffer
+	if err := NewSQL(Postgres).Emit(&buf, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `"status" TEXT NOT NULL`) {
+		t.Errorf("Expected enum column, got:\n%s", out)
+	}
+	if !strings.Contains(out, `CHECK ("status" IN ('draft', 'published'))`) {
+		t.Errorf("Expected enum CHECK constraint, got:\n%s", out)
+	}
+	if !strings.Contains(out, `"price" NUMERIC(10,2)`) {
+		t.Errorf("Expected a precision/scale decimal column, got:\n%s", out)
+	}
+	if strings.Contains(out, `"price" NUMERIC(10,2) NOT NULL`) {
+		t.Errorf("Expected the nullable price column to omit NOT NULL, got:\n%s", out)
+	}
+	if !strings.Contains(out, `FOREIGN KEY ("article_id") REFERENCES "Article" ("id")`) {
+		t.Errorf("Expected foreign key clause, got:\n%s", out)
+	}
+	if strings.Index(out, `CREATE TABLE "Article"`) > strings.Index(out, `CREATE TABLE "Comment"`) {
+		t.Errorf("Expected Article before Comment (ref dependency order), got:\n%s", out)
+	}
+}
+
+func TestSQLInsertStatements(t *testing.T) {
+	doc := mustDoc(t, `#! User
+#@ id:uint, name:str
+1, Alice
+`)
+
+	var buf bytes.Buffer
+	if err := NewSQL(MySQL).Emit(&buf, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "INSERT INTO `User` (`id`, `name`) VALUES (1, 'Alice');") {
+		t.Errorf("Expected an INSERT statement, got:\n%s", out)
+	}
+}
+
+func TestSQLPostgresUsesCopy(t *testing.T) {
+	doc := mustDoc(t, `#! User
+#@ id:uint, name:str
+1, Alice
+`)
+
+	var buf bytes.Buffer
+	if err := NewSQL(Postgres).Emit(&buf, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `COPY "User" ("id", "name") FROM STDIN;`) {
+		t.Errorf("Expected a COPY block, got:\n%s", out)
+	}
+	if !strings.Contains(out, "1\tAlice\n\\.\n") {
+		t.Errorf("Expected tab-delimited COPY data, got:\n%s", out)
+	}
+}
+
+func TestSQLDialectIntegerSpellings(t *testing.T) {
+	doc := mustDoc(t, "#! Metric\n#@ id:i64\n1\n")
+
+	var pg, my, lite bytes.Buffer
+	if err := NewSQL(Postgres).Emit(&pg, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+	if err := NewSQL(MySQL).Emit(&my, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+	if err := NewSQL(SQLite).Emit(&lite, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+
+	if !strings.Contains(pg.String(), `"id" INT8 NOT NULL`) {
+		t.Errorf("Expected Postgres to spell i64 as INT8, got:\n%s", pg.String())
+	}
+	if !strings.Contains(my.String(), "`id` BIGINT NOT NULL") {
+		t.Errorf("Expected MySQL to spell i64 as BIGINT, got:\n%s", my.String())
+	}
+	if !strings.Contains(lite.String(), `"id" INTEGER NOT NULL`) {
+		t.Errorf("Expected SQLite to spell i64 as INTEGER, got:\n%s", lite.String())
+	}
+}
+
+func TestSQLArrayFieldOnUnvalidatedDocument(t *testing.T) {
+	// ParseString alone never runs parser.Validate, so an array column's row value is
+	// still the raw "[a|b|c]" string cleanField produced, not []any. The emitter must
+	// split it itself rather than silently treating the failed []any assertion as empty.
+	doc := mustDoc(t, `#! Tag
+#@ id:uint, labels:str[]
+1, [one|two|three]
+`)
+
+	var buf bytes.Buffer
+	if err := NewSQL(MySQL).Emit(&buf, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `'one|two|three'`) {
+		t.Errorf("Expected array literal with all elements, got:\n%s", out)
+	}
+
+	var copyBuf bytes.Buffer
+	if err := NewSQL(Postgres).Emit(&copyBuf, doc); err != nil {
+		t.Fatalf("Emit error: %v", err)
+	}
+	if !strings.Contains(copyBuf.String(), "1\t{one,two,three}\n") {
+		t.Errorf("Expected COPY array literal with all elements, got:\n%s", copyBuf.String())
+	}
+}
+
+func TestSQLCycleDetection(t *testing.T) {
+	doc := &parser.Document{Tables: []parser.Table{
+		{
+			Name:    "A",
+			Columns: []parser.Column{{Name: "b_id", Type: "ref(B.id)"}},
+			Rows:    [][]any{{int64(1)}},
+		},
+		{
+			Name:    "B",
+			Columns: []parser.Column{{Name: "a_id", Type: "ref(A.id)"}},
+			Rows:    [][]any{{int64(1)}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := NewSQL(Postgres).Emit(&buf, doc); err == nil {
+		t.Error("Expected a cycle detection error")
+	}
+}
+
+func TestSQLSelfReferenceIsNotACycle(t *testing.T) {
+	doc := &parser.Document{Tables: []parser.Table{
+		{
+			Name:    "Employee",
+			Columns: []parser.Column{{Name: "id", Type: "uint"}, {Name: "manager_id", Type: "ref(Employee.id)?"}},
+			Rows:    [][]any{{int64(1), nil}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := NewSQL(Postgres).Emit(&buf, doc); err != nil {
+		t.Errorf("Expected a self-reference to emit without a cycle error, got: %v", err)
+	}
+}
@@ -0,0 +1,21 @@
+package emit
+
+import (
+	"io"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+// jsonEmitter renders a Document via parser.WriteJSON.
+type jsonEmitter struct {
+	pretty bool
+}
+
+// NewJSON returns an Emitter that writes doc as JSON, indented when pretty is true.
+func NewJSON(pretty bool) Emitter {
+	return jsonEmitter{pretty: pretty}
+}
+
+func (e jsonEmitter) Emit(w io.Writer, doc *parser.Document) error {
+	return parser.WriteJSON(w, doc, e.pretty)
+}
@@ -0,0 +1,24 @@
+package emit
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+// parquetEmitter is registered so --format=parquet is recognized rather than rejected
+// as an unknown format, but it cannot actually produce Parquet: a real writer needs
+// row-group batching, Thrift-encoded footers, and compression codecs, none of which are
+// vendored into this module. Emit always fails until a proper writer is plugged in, e.g.
+// via Register(FormatParquet, ...) from a caller that has the dependency available.
+type parquetEmitter struct{}
+
+// NewParquet returns the placeholder Emitter for the "parquet" format.
+func NewParquet() Emitter {
+	return parquetEmitter{}
+}
+
+func (parquetEmitter) Emit(w io.Writer, doc *parser.Document) error {
+	return fmt.Errorf("emit: parquet output is not implemented in this module (no columnar writer dependency vendored); register a replacement Emitter via emit.Register")
+}
@@ -0,0 +1,286 @@
+package emit
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+// sqlEmitter renders a Document as a SQL script: one CREATE TABLE per table, in
+// ref(...) dependency order, followed by its rows as either INSERT statements or (for
+// dialects with UseCopy set) a COPY FROM STDIN block.
+type sqlEmitter struct {
+	dialect Dialect
+}
+
+// NewSQL returns an Emitter that writes doc as a SQL script in the given Dialect.
+func NewSQL(dialect Dialect) Emitter {
+	return sqlEmitter{dialect: dialect}
+}
+
+func (e sqlEmitter) Emit(w io.Writer, doc *parser.Document) error {
+	ordered, err := topoSortTables(doc)
+	if err != nil {
+		return err
+	}
+
+	for i, table := range ordered {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if err := e.writeCreateTable(w, doc, table); err != nil {
+			return err
+		}
+		if err := e.writeRows(w, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// topoSortTables orders doc.Tables so that a table referenced by ref(T.c) is emitted
+// before any table that references it, returning an error if the ref graph has a cycle.
+// A column that refs its own table does not count as a cycle: the reference is only
+// enforceable once the table already exists, which CREATE TABLE ... FOREIGN KEY allows.
+func topoSortTables(doc *parser.Document) ([]*parser.Table, error) {
+	byName := make(map[string]int, len(doc.Tables))
+	for i := range doc.Tables {
+		byName[doc.Tables[i].Name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(doc.Tables))
+	ordered := make([]*parser.Table, 0, len(doc.Tables))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("emit: cycle detected in ref(...) dependencies involving table %q", doc.Tables[i].Name)
+		}
+		state[i] = visiting
+
+		for _, col := range doc.Tables[i].Columns {
+			ts := parseType(col.Type)
+			if ts.Kind != "ref" || len(ts.Params) == 0 {
+				continue
+			}
+			j, ok := byName[ts.Params[0]]
+			if !ok || j == i {
+				continue
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+
+		state[i] = visited
+		ordered = append(ordered, &doc.Tables[i])
+		return nil
+	}
+
+	for i := range doc.Tables {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+func (e sqlEmitter) writeCreateTable(w io.Writer, doc *parser.Document, table *parser.Table) error {
+	q := e.dialect.QuoteIdent
+
+	var lines []string
+	var checks []string
+	var fks []string
+
+	for _, col := range table.Columns {
+		ts := parseType(col.Type)
+		colType := e.dialect.ColumnType(doc, ts)
+		if ts.Array {
+			colType = e.dialect.WrapArray(colType)
+		}
+
+		line := fmt.Sprintf("%s %s", q(col.Name), colType)
+		if !ts.Nullable {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+
+		if ts.Kind == "enum" && len(ts.Params) > 0 {
+			values := make([]string, len(ts.Params))
+			for i, v := range ts.Params {
+				values[i] = sqlLiteral(v)
+			}
+			checks = append(checks, fmt.Sprintf("CHECK (%s IN (%s))", q(col.Name), strings.Join(values, ", ")))
+		}
+
+		if ts.Kind == "ref" && len(ts.Params) >= 2 {
+			fks = append(fks, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)", q(col.Name), q(ts.Params[0]), q(ts.Params[1])))
+		}
+	}
+
+	lines = append(lines, checks...)
+	lines = append(lines, fks...)
+
+	if _, err := fmt.Fprintf(w, "CREATE TABLE %s (\n", q(table.Name)); err != nil {
+		return err
+	}
+	for i, line := range lines {
+		sep := ","
+		if i == len(lines)-1 {
+			sep = ""
+		}
+		if _, err := fmt.Fprintf(w, "    %s%s\n", line, sep); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, ");\n")
+	return err
+}
+
+func (e sqlEmitter) writeRows(w io.Writer, table *parser.Table) error {
+	if len(table.Rows) == 0 {
+		return nil
+	}
+
+	colTypes := make([]typeSpec, len(table.Columns))
+	colNames := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		colTypes[i] = parseType(col.Type)
+		colNames[i] = e.dialect.QuoteIdent(col.Name)
+	}
+
+	if e.dialect.UseCopy {
+		return e.writeCopy(w, table, colNames, colTypes)
+	}
+
+	tableIdent := e.dialect.QuoteIdent(table.Name)
+	for _, row := range table.Rows {
+		values := make([]string, len(row))
+		for i, v := range row {
+			values[i] = e.literalFor(v, colTypes[i])
+		}
+		if _, err := fmt.Fprintf(w, "INSERT INTO %s (%s) VALUES (%s);\n", tableIdent, strings.Join(colNames, ", "), strings.Join(values, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e sqlEmitter) writeCopy(w io.Writer, table *parser.Table, colNames []string, colTypes []typeSpec) error {
+	if _, err := fmt.Fprintf(w, "COPY %s (%s) FROM STDIN;\n", e.dialect.QuoteIdent(table.Name), strings.Join(colNames, ", ")); err != nil {
+		return err
+	}
+	for _, row := range table.Rows {
+		fields := make([]string, len(row))
+		for i, v := range row {
+			fields[i] = copyFieldFor(v, colTypes[i])
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\\.\n")
+	return err
+}
+
+// arrayElems returns the element values of an array-typed field. A validated Document
+// already holds v as []any, but an emitter can also run against a raw, unvalidated
+// Document (e.g. the CLI never calls parser.Validate), in which case v is still the
+// delimited string cleanField produced, so it falls back to parser.SplitArray.
+func arrayElems(v any) []any {
+	if elems, ok := v.([]any); ok {
+		return elems
+	}
+	raw, err := parser.SplitArray(v)
+	if err != nil {
+		return nil
+	}
+	elems := make([]any, len(raw))
+	for i, s := range raw {
+		elems[i] = s
+	}
+	return elems
+}
+
+// literalFor renders v as a SQL literal for a column of type ts. Array values become a
+// native ARRAY[...] literal when the dialect supports one, otherwise a single delimited
+// string literal matching the WrapArray TEXT fallback.
+func (e sqlEmitter) literalFor(v any, ts typeSpec) string {
+	if !ts.Array {
+		return sqlLiteral(v)
+	}
+
+	elems := arrayElems(v)
+	if e.dialect.NativeArray {
+		parts := make([]string, len(elems))
+		for i, el := range elems {
+			parts[i] = sqlLiteral(el)
+		}
+		return "ARRAY[" + strings.Join(parts, ", ") + "]"
+	}
+
+	parts := make([]string, len(elems))
+	for i, el := range elems {
+		parts[i] = fmt.Sprintf("%v", el)
+	}
+	return sqlLiteral(strings.Join(parts, "|"))
+}
+
+// sqlLiteral renders a value produced by the parser (int64, float64, bool, string, or
+// nil) as a SQL literal. The ANSI '' escaping it uses for strings is accepted by
+// Postgres, MySQL, and SQLite alike.
+func sqlLiteral(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + strings.ReplaceAll(x, "'", "''") + "'"
+	case bool:
+		if x {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	default:
+		return "'" + strings.ReplaceAll(fmt.Sprintf("%v", x), "'", "''") + "'"
+	}
+}
+
+// copyFieldFor renders a value in Postgres COPY's tab-delimited text format: \N for
+// NULL, backslash-escaped control characters otherwise, and {a,b} for arrays.
+func copyFieldFor(v any, ts typeSpec) string {
+	if v == nil {
+		return `\N`
+	}
+	if ts.Array {
+		elems := arrayElems(v)
+		parts := make([]string, len(elems))
+		for i, el := range elems {
+			parts[i] = fmt.Sprintf("%v", el)
+		}
+		return "{" + strings.Join(parts, ",") + "}"
+	}
+	return copyEscape(fmt.Sprintf("%v", v))
+}
+
+var copyEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func copyEscape(s string) string {
+	return copyEscaper.Replace(s)
+}
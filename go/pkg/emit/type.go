@@ -0,0 +1,14 @@
+package emit
+
+import "github.com/jiriknesl/3tl/pkg/parser"
+
+// typeSpec is a local alias for parser.TypeSpec: dialect.go and sql.go were written
+// before the type moved there and it isn't worth renaming every call site.
+type typeSpec = parser.TypeSpec
+
+// parseType parses a Column.Type string into a typeSpec. It delegates entirely to
+// parser.ParseTypeSpec, which pkg/codegen shares too, instead of keeping its own
+// independent copy of the parsing logic.
+func parseType(s string) typeSpec {
+	return parser.ParseTypeSpec(s)
+}
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeStrategy decides how the rows (and, for UnionSchema, the columns) of an incoming
+// table are folded into an existing table of the same name during Document.Merge.
+type MergeStrategy interface {
+	merge(dst, src *Table) error
+}
+
+// Merge folds other's tables into d using strategy. A table present in both documents is
+// merged in place via strategy; a table that only exists in other is appended as-is.
+func (d *Document) Merge(other *Document, strategy MergeStrategy) error {
+	byName := make(map[string]int, len(d.Tables))
+	for i := range d.Tables {
+		byName[d.Tables[i].Name] = i
+	}
+
+	for _, src := range other.Tables {
+		src := src
+		if i, ok := byName[src.Name]; ok {
+			if err := strategy.merge(&d.Tables[i], &src); err != nil {
+				return fmt.Errorf("merge table %q: %w", src.Name, err)
+			}
+			continue
+		}
+
+		d.Tables = append(d.Tables, src)
+		byName[src.Name] = len(d.Tables) - 1
+	}
+
+	return nil
+}
+
+type appendRowsStrategy struct{}
+
+func (appendRowsStrategy) merge(dst, src *Table) error {
+	dst.Rows = append(dst.Rows, src.Rows...)
+	return nil
+}
+
+// AppendRows concatenates every row of the incoming table onto the existing one,
+// without regard to duplicates.
+func AppendRows() MergeStrategy { return appendRowsStrategy{} }
+
+type replaceByKeyStrategy struct{ keyCols []string }
+
+func (s replaceByKeyStrategy) merge(dst, src *Table) error {
+	dstKey, err := columnIndices(dst.Columns, s.keyCols)
+	if err != nil {
+		return err
+	}
+	srcKey, err := columnIndices(src.Columns, s.keyCols)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]int, len(dst.Rows))
+	for i, row := range dst.Rows {
+		existing[rowKey(row, dstKey)] = i
+	}
+
+	for _, row := range src.Rows {
+		key := rowKey(row, srcKey)
+		if i, ok := existing[key]; ok {
+			dst.Rows[i] = row
+			continue
+		}
+		dst.Rows = append(dst.Rows, row)
+		existing[key] = len(dst.Rows) - 1
+	}
+
+	return nil
+}
+
+// ReplaceByKey replaces rows whose keyCols match an incoming row with that incoming
+// row, appending rows whose key is not already present. keyCols must name columns
+// present in both tables.
+func ReplaceByKey(keyCols ...string) MergeStrategy {
+	return replaceByKeyStrategy{keyCols: keyCols}
+}
+
+type unionSchemaStrategy struct{}
+
+func (unionSchemaStrategy) merge(dst, src *Table) error {
+	colIdx := make(map[string]int, len(dst.Columns))
+	for i, c := range dst.Columns {
+		colIdx[c.Name] = i
+	}
+
+	for _, c := range src.Columns {
+		if _, ok := colIdx[c.Name]; ok {
+			continue
+		}
+		colIdx[c.Name] = len(dst.Columns)
+		dst.Columns = append(dst.Columns, c)
+		for i := range dst.Rows {
+			dst.Rows[i] = append(dst.Rows[i], nil)
+		}
+	}
+
+	for _, row := range src.Rows {
+		merged := make([]any, len(dst.Columns))
+		for i, c := range src.Columns {
+			if i < len(row) {
+				merged[colIdx[c.Name]] = row[i]
+			}
+		}
+		dst.Rows = append(dst.Rows, merged)
+	}
+
+	return nil
+}
+
+// UnionSchema merges incoming rows even when the two tables' schemas differ: columns
+// are unioned by name, new columns are backfilled with nil on existing rows, and
+// incoming rows are reshaped to the merged column order.
+func UnionSchema() MergeStrategy { return unionSchemaStrategy{} }
+
+func columnIndices(columns []Column, names []string) ([]int, error) {
+	idx := make([]int, len(names))
+	for i, name := range names {
+		found := -1
+		for ci, c := range columns {
+			if c.Name == name {
+				found = ci
+				break
+			}
+		}
+		if found == -1 {
+			return nil, fmt.Errorf("key column %q not found", name)
+		}
+		idx[i] = found
+	}
+	return idx, nil
+}
+
+func rowKey(row []any, idx []int) string {
+	parts := make([]string, len(idx))
+	for i, ci := range idx {
+		if ci < len(row) {
+			parts[i] = fmt.Sprintf("%v", row[ci])
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
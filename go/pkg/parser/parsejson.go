@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ParseJSON parses the JSON representation produced by ToJSON/WriteJSON back into a
+// Document. Numbers are decoded as int64 when they carry no fractional part and as
+// float64 otherwise, matching the types ParseString produces for the same values.
+func ParseJSON(r io.Reader) (*Document, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var doc Document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	for ti := range doc.Tables {
+		for ri := range doc.Tables[ti].Rows {
+			for ci, value := range doc.Tables[ti].Rows[ri] {
+				doc.Tables[ti].Rows[ri][ci] = normalizeJSONValue(value)
+			}
+		}
+	}
+
+	return &doc, nil
+}
+
+func normalizeJSONValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = normalizeJSONValue(e)
+		}
+		return out
+	default:
+		return value
+	}
+}
@@ -20,9 +20,9 @@ type Document struct {
 
 // Table represents a 3TL table with schema and data
 type Table struct {
-	Name    string     `json:"name"`
-	Columns []Column   `json:"columns"`
-	Rows    [][]any    `json:"rows"`
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+	Rows    [][]any  `json:"rows"`
 }
 
 // Column represents a column definition
@@ -31,29 +31,32 @@ type Column struct {
 	Type string `json:"type"`
 }
 
-// Internal parsing structures
-type file struct {
-	Lines []line `parser:"@@*"`
-}
-
+// Internal parsing structures. Each one is parsed a single line at a time by
+// lineParser (see stream.go) rather than against the whole file at once.
 type line struct {
 	Comment     *comment     `parser:"  @@"`
 	TableHeader *tableHeader `parser:"| @@"`
 	SchemaDef   *schemaDef   `parser:"| @@"`
-	DataRow     *dataRow     `parser:"| @@"`
 	EmptyLine   *string      `parser:"| @Newline"`
+	DataRow     *dataRow     `parser:"| @@"`
 }
 
+// comment matches a plain "# ..." line, lexed as a single Comment token since it
+// doesn't start with the "#!" or "#@" marker sequences below.
 type comment struct {
-	Text string `parser:"'#' ( ![@!] @( ~Newline )* )? Newline"`
+	Text string `parser:"@Comment Newline"`
 }
 
+// tableHeader matches "#!Name", lexed as a single fused TableMarker token rather than
+// separate '#' and '!' tokens.
 type tableHeader struct {
-	Name string `parser:"'#' '!' @Ident Newline"`
+	Name string `parser:"TableMarker @Ident Newline"`
 }
 
+// schemaDef matches "#@col:type, ...", lexed as a single fused SchemaMarker token
+// rather than separate '#' and '@' tokens.
 type schemaDef struct {
-	Columns []columnDef `parser:"'#' '@' @@ ( ',' @@ )* Newline"`
+	Columns []columnDef `parser:"SchemaMarker @@ ( ',' @@ )* Newline"`
 }
 
 type columnDef struct {
@@ -68,9 +71,14 @@ type dataRow struct {
 	Fields []field `parser:"@@ ( ',' @@ )* Newline"`
 }
 
+// Quoted and Unquoted are matched in sequence, not as alternatives: an entirely empty
+// field (two adjacent commas, or a trailing comma before the newline) must match both
+// as zero-width, and participle panics if a zero-width match is chosen between
+// alternatives of a disjunction instead of simply being the unremarkable result of an
+// optional/repeated group.
 type field struct {
-	Quoted   *string `parser:"  @String"`
-	Unquoted *string `parser:"| @( Ident | Number | '.' | '-' | '@' | '_' | '/' | ':' | ~( ',' | Newline | '#' ) )*"`
+	Quoted   *string `parser:"  @String?"`
+	Unquoted *string `parser:"  @( Ident | Number | '.' | '-' | '@' | '_' | '/' | ':' | ~( ',' | Newline | '#' ) )*"`
 }
 
 var (
@@ -82,35 +90,68 @@ var (
 		{Name: "String", Pattern: `"(?:[^"]|"")*"`},
 		{Name: "Number", Pattern: `-?\d+(?:\.\d+)?`},
 		{Name: "Ident", Pattern: `[a-zA-Z_\x{00C0}-\x{024F}\x{1E00}-\x{1EFF}\x{0400}-\x{04FF}\x{0370}-\x{03FF}\x{4E00}-\x{9FFF}\x{3040}-\x{309F}\x{30A0}-\x{30FF}][a-zA-Z0-9_\x{00C0}-\x{024F}\x{1E00}-\x{1EFF}\x{0400}-\x{04FF}\x{0370}-\x{03FF}\x{4E00}-\x{9FFF}\x{3040}-\x{309F}\x{30A0}-\x{30FF}]*`},
-		{Name: "Punct", Pattern: `[-[!@#$%^&*()+_={}\|:;"'<,>.?/]|\[\]`},
+		{Name: "Punct", Pattern: `[-[\]!@#$%^&*()+_={}\|:;"'<,>.?/]`},
 		{Name: "Newline", Pattern: `\r?\n`},
 		{Name: "whitespace", Pattern: `[ \t]+`},
 	})
 
-	parser = participle.MustBuild[file](
+	lineParser = participle.MustBuild[line](
 		participle.Lexer(lex),
 		participle.Elide("whitespace"),
 	)
 )
 
-// ParseString parses a 3TL string and returns a Document
+// ParseString parses a 3TL string and returns a Document. It is a thin wrapper around
+// NewStreamParser that collects every event into memory.
 func ParseString(input string) (*Document, error) {
-	parsed, err := parser.ParseString("", input)
+	return parseReader(strings.NewReader(input))
+}
+
+// ParseFile parses a 3TL file and returns a Document.
+func ParseFile(filename string) (*Document, error) {
+	f, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("parse error: %w", err)
+		return nil, fmt.Errorf("open file: %w", err)
 	}
+	defer f.Close()
 
-	return transform(parsed), nil
+	return parseReader(f)
 }
 
-// ParseFile parses a 3TL file and returns a Document
-func ParseFile(filename string) (*Document, error) {
-	data, err := os.ReadFile(filename)
+// parseReader drives a StreamParser to completion and assembles its events into a
+// Document.
+func parseReader(r io.Reader) (*Document, error) {
+	doc := &Document{Tables: []Table{}}
+	var current *Table
+
+	sp := NewStreamParser(r)
+	err := sp.Decode(func(ev Event) error {
+		switch e := ev.(type) {
+		case TableStartEvent:
+			current = &Table{Name: e.Name, Columns: []Column{}, Rows: [][]any{}}
+		case SchemaEvent:
+			if current == nil {
+				return fmt.Errorf("schema line outside of a table")
+			}
+			current.Columns = append(current.Columns, e.Columns...)
+		case RowEvent:
+			if current == nil {
+				return fmt.Errorf("data row outside of a table")
+			}
+			current.Rows = append(current.Rows, e.Values)
+		case TableEndEvent:
+			if current != nil {
+				doc.Tables = append(doc.Tables, *current)
+				current = nil
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("read file: %w", err)
+		return nil, fmt.Errorf("parse error: %w", err)
 	}
 
-	return ParseString(string(data))
+	return doc, nil
 }
 
 // ToJSON converts a Document to JSON
@@ -142,49 +183,6 @@ func WriteJSON(w io.Writer, doc *Document, pretty bool) error {
 	return err
 }
 
-// Transform parsed structure into Document
-func transform(f *file) *Document {
-	doc := &Document{Tables: []Table{}}
-	var currentTable *Table
-
-	for _, line := range f.Lines {
-		if line.TableHeader != nil {
-			// Save previous table if exists
-			if currentTable != nil {
-				doc.Tables = append(doc.Tables, *currentTable)
-			}
-			// Start new table
-			currentTable = &Table{
-				Name:    line.TableHeader.Name,
-				Columns: []Column{},
-				Rows:    [][]any{},
-			}
-		} else if line.SchemaDef != nil && currentTable != nil {
-			// Add columns to current table
-			for _, col := range line.SchemaDef.Columns {
-				currentTable.Columns = append(currentTable.Columns, Column{
-					Name: col.Name,
-					Type: formatType(&col),
-				})
-			}
-		} else if line.DataRow != nil && currentTable != nil {
-			// Add data row to current table
-			row := make([]any, 0, len(line.DataRow.Fields))
-			for _, field := range line.DataRow.Fields {
-				row = append(row, cleanField(&field))
-			}
-			currentTable.Rows = append(currentTable.Rows, row)
-		}
-	}
-
-	// Add last table
-	if currentTable != nil {
-		doc.Tables = append(doc.Tables, *currentTable)
-	}
-
-	return doc
-}
-
 // Format type definition into string representation
 func formatType(col *columnDef) string {
 	typeName := strings.ToLower(col.TypeName)
@@ -218,19 +216,20 @@ func formatType(col *columnDef) string {
 
 // Clean and convert field value
 func cleanField(f *field) any {
-	var value string
-
 	if f.Quoted != nil {
-		// Remove quotes and unescape doubled quotes
-		value = *f.Quoted
+		// A quoted field is always a string: the author used quotes specifically to
+		// stop "42" or "true" from being read back as a number or bool.
+		value := *f.Quoted
 		value = strings.TrimPrefix(value, "\"")
 		value = strings.TrimSuffix(value, "\"")
 		value = strings.ReplaceAll(value, "\"\"", "\"")
-	} else if f.Unquoted != nil {
-		value = strings.TrimSpace(*f.Unquoted)
-	} else {
+		return value
+	}
+
+	if f.Unquoted == nil {
 		return nil
 	}
+	value := strings.TrimSpace(*f.Unquoted)
 
 	// Empty or null
 	if value == "" || strings.ToLower(value) == "null" {
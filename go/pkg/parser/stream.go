@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Event is implemented by every event a StreamParser can emit.
+type Event interface {
+	isEvent()
+}
+
+// TableStartEvent is emitted when a `#!` table header line is parsed.
+type TableStartEvent struct {
+	Name string
+}
+
+// SchemaEvent is emitted for each `#@` schema line parsed within a table. A table with
+// more than one schema line (unusual, but not rejected) produces one SchemaEvent per
+// line, each contributing additional columns.
+type SchemaEvent struct {
+	Columns []Column
+}
+
+// RowEvent is emitted for each data row parsed within a table.
+type RowEvent struct {
+	TableName string
+	RowIndex  int
+	Values    []any
+}
+
+// TableEndEvent is emitted once a table's lines are exhausted, either because a new
+// `#!` header or the end of input was reached.
+type TableEndEvent struct {
+	Name string
+}
+
+func (TableStartEvent) isEvent() {}
+func (SchemaEvent) isEvent()     {}
+func (RowEvent) isEvent()        {}
+func (TableEndEvent) isEvent()   {}
+
+// EventHandler processes a single Event produced by StreamParser.Decode.
+type EventHandler func(Event) error
+
+// StreamParser parses 3TL text incrementally, one logical line at a time, so callers
+// can validate or apply rows as they arrive instead of waiting for the full document.
+// It buffers only the current line.
+type StreamParser struct {
+	scanner *bufio.Scanner
+
+	pending []Event
+	done    bool
+
+	currentTable string
+	inTable      bool
+	rowIndex     int
+}
+
+// NewStreamParser returns a StreamParser reading 3TL text from r.
+func NewStreamParser(r io.Reader) *StreamParser {
+	return &StreamParser{scanner: bufio.NewScanner(r)}
+}
+
+// Next returns the next Event, or io.EOF once the input is exhausted.
+func (p *StreamParser) Next() (Event, error) {
+	for {
+		if len(p.pending) > 0 {
+			ev := p.pending[0]
+			p.pending = p.pending[1:]
+			return ev, nil
+		}
+
+		if p.done {
+			return nil, io.EOF
+		}
+
+		if !p.scanner.Scan() {
+			if err := p.scanner.Err(); err != nil {
+				return nil, fmt.Errorf("read line: %w", err)
+			}
+			p.done = true
+			if p.inTable {
+				p.inTable = false
+				return TableEndEvent{Name: p.currentTable}, nil
+			}
+			return nil, io.EOF
+		}
+
+		parsed, err := parseLine(p.scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("stream parse error: %w", err)
+		}
+
+		switch {
+		case parsed.Comment != nil, parsed.EmptyLine != nil:
+			continue
+
+		case parsed.TableHeader != nil:
+			if p.inTable {
+				p.pending = append(p.pending, TableEndEvent{Name: p.currentTable})
+			}
+			p.currentTable = parsed.TableHeader.Name
+			p.inTable = true
+			p.rowIndex = 0
+			p.pending = append(p.pending, TableStartEvent{Name: p.currentTable})
+
+		case parsed.SchemaDef != nil:
+			cols := make([]Column, len(parsed.SchemaDef.Columns))
+			for i := range parsed.SchemaDef.Columns {
+				cols[i] = Column{
+					Name: parsed.SchemaDef.Columns[i].Name,
+					Type: formatType(&parsed.SchemaDef.Columns[i]),
+				}
+			}
+			return SchemaEvent{Columns: cols}, nil
+
+		case parsed.DataRow != nil:
+			row := make([]any, len(parsed.DataRow.Fields))
+			for i := range parsed.DataRow.Fields {
+				row[i] = cleanField(&parsed.DataRow.Fields[i])
+			}
+			ev := RowEvent{TableName: p.currentTable, RowIndex: p.rowIndex, Values: row}
+			p.rowIndex++
+			return ev, nil
+		}
+	}
+}
+
+// Decode calls handler for every event until the input is exhausted or handler (or
+// parsing) returns an error.
+func (p *StreamParser) Decode(handler EventHandler) error {
+	for {
+		ev, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handler(ev); err != nil {
+			return err
+		}
+	}
+}
+
+// parseLine parses a single logical line of 3TL text. raw must not include its
+// trailing newline; one is appended internally to satisfy the line grammar. Participle
+// panics instead of returning an error for some grammar/lexer mismatches (e.g. a
+// repetition that matches zero-width); that's recovered here and surfaced as a normal
+// error so a caller never has to deal with a parse crashing the process.
+func parseLine(raw string) (parsed *line, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			parsed = nil
+			err = fmt.Errorf("parse line %q: %v", raw, r)
+		}
+	}()
+	return lineParser.ParseString("", raw+"\n")
+}
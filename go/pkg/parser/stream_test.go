@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamParserEventSequence(t *testing.T) {
+	input := `#! User
+#@ id:uint, name:str
+1, Alice
+2, Bob
+`
+	sp := NewStreamParser(strings.NewReader(input))
+
+	var kinds []string
+	for {
+		ev, err := sp.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next error: %v", err)
+		}
+		switch e := ev.(type) {
+		case TableStartEvent:
+			kinds = append(kinds, "start:"+e.Name)
+		case SchemaEvent:
+			kinds = append(kinds, "schema")
+		case RowEvent:
+			kinds = append(kinds, "row")
+		case TableEndEvent:
+			kinds = append(kinds, "end:"+e.Name)
+		}
+	}
+
+	expected := []string{"start:User", "schema", "row", "row", "end:User"}
+	if len(kinds) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, kinds)
+	}
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, kinds)
+			break
+		}
+	}
+}
+
+func TestStreamParserMultipleTables(t *testing.T) {
+	input := `#! User
+#@ id:uint
+1
+
+#! Post
+#@ id:uint
+1
+`
+	sp := NewStreamParser(strings.NewReader(input))
+
+	var starts, ends []string
+	if err := sp.Decode(func(ev Event) error {
+		switch e := ev.(type) {
+		case TableStartEvent:
+			starts = append(starts, e.Name)
+		case TableEndEvent:
+			ends = append(ends, e.Name)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+
+	if len(starts) != 2 || starts[0] != "User" || starts[1] != "Post" {
+		t.Errorf("Expected starts [User Post], got %v", starts)
+	}
+	if len(ends) != 2 || ends[0] != "User" || ends[1] != "Post" {
+		t.Errorf("Expected ends [User Post], got %v", ends)
+	}
+}
+
+func TestStreamParserRowIndexAndTableName(t *testing.T) {
+	input := `#! Task
+#@ id:uint
+1
+2
+3
+`
+	sp := NewStreamParser(strings.NewReader(input))
+
+	var rows []RowEvent
+	if err := sp.Decode(func(ev Event) error {
+		if row, ok := ev.(RowEvent); ok {
+			rows = append(rows, row)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	for i, row := range rows {
+		if row.TableName != "Task" {
+			t.Errorf("Expected table 'Task', got %q", row.TableName)
+		}
+		if row.RowIndex != i {
+			t.Errorf("Expected row index %d, got %d", i, row.RowIndex)
+		}
+	}
+}
+
+func TestStreamParserDecodeErrorPropagates(t *testing.T) {
+	input := `#! User
+#@ id:uint
+1
+`
+	sp := NewStreamParser(strings.NewReader(input))
+
+	err := sp.Decode(func(ev Event) error {
+		if _, ok := ev.(RowEvent); ok {
+			return fmt.Errorf("boom")
+		}
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected Decode to propagate handler error")
+	}
+}
@@ -0,0 +1,366 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError describes a single schema violation found while validating a Document
+// against its own column declarations.
+type ValidationError struct {
+	Table  string
+	Row    int
+	Column string
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("%s[%d]: %s", e.Table, e.Row, e.Reason)
+	}
+	return fmt.Sprintf("%s[%d].%s: %s", e.Table, e.Row, e.Column, e.Reason)
+}
+
+// TypeSpec is the parsed form of a Column.Type string, e.g. "decimal(10,2)[]?". It is
+// the single shared representation of a column type: pkg/codegen and pkg/emit both need
+// it and import this package already, so they call ParseTypeSpec instead of keeping
+// their own copies.
+type TypeSpec struct {
+	Kind     string
+	Params   []string
+	Array    bool
+	Nullable bool
+}
+
+// ParseTypeSpec parses a Column.Type string into a TypeSpec.
+func ParseTypeSpec(s string) TypeSpec {
+	var spec TypeSpec
+
+	if strings.HasSuffix(s, "?") {
+		spec.Nullable = true
+		s = strings.TrimSuffix(s, "?")
+	}
+	if strings.HasSuffix(s, "[]") {
+		spec.Array = true
+		s = strings.TrimSuffix(s, "[]")
+	}
+
+	if idx := strings.Index(s, "("); idx != -1 && strings.HasSuffix(s, ")") {
+		spec.Kind = s[:idx]
+		params := s[idx+1 : len(s)-1]
+		sep := ","
+		switch spec.Kind {
+		case "enum":
+			sep = "|"
+		case "ref":
+			sep = "."
+		}
+		for _, p := range strings.Split(params, sep) {
+			spec.Params = append(spec.Params, strings.TrimSpace(p))
+		}
+	} else {
+		spec.Kind = strings.ToLower(s)
+	}
+
+	return spec
+}
+
+var intBounds = map[string][2]int64{
+	"i8":  {math.MinInt8, math.MaxInt8},
+	"i16": {math.MinInt16, math.MaxInt16},
+	"i32": {math.MinInt32, math.MaxInt32},
+	"i64": {math.MinInt64, math.MaxInt64},
+	"int": {math.MinInt64, math.MaxInt64},
+}
+
+var uintBounds = map[string]uint64{
+	"u8":   math.MaxUint8,
+	"u16":  math.MaxUint16,
+	"u32":  math.MaxUint32,
+	"u64":  math.MaxUint64,
+	"uint": math.MaxUint64,
+}
+
+// TimeLayouts lists every layout a date/time/datetime/timestamp column value is
+// accepted in, tried in order. Exported so generated loader code (pkg/codegen) can
+// recognize the same formats Validate does instead of only handling RFC3339.
+var TimeLayouts = []string{
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"15:04:05",
+}
+
+// Validate checks every row in doc against its table's declared schema and returns one
+// ValidationError per violation found. Values that validate successfully are coerced in
+// place (e.g. dates are re-emitted as canonical RFC3339 strings, array fields are split
+// into []any), so a caller should treat doc as validated-and-normalized once Validate
+// returns no errors.
+func Validate(doc *Document) []ValidationError {
+	var errs []ValidationError
+
+	tablesByName := make(map[string]*Table, len(doc.Tables))
+	for i := range doc.Tables {
+		tablesByName[doc.Tables[i].Name] = &doc.Tables[i]
+	}
+
+	for ti := range doc.Tables {
+		table := &doc.Tables[ti]
+		for ri, row := range table.Rows {
+			if len(row) != len(table.Columns) {
+				errs = append(errs, ValidationError{
+					Table:  table.Name,
+					Row:    ri,
+					Reason: fmt.Sprintf("expected %d fields, got %d", len(table.Columns), len(row)),
+				})
+				continue
+			}
+
+			for ci, col := range table.Columns {
+				spec := ParseTypeSpec(col.Type)
+				value := row[ci]
+
+				if value == nil {
+					if !spec.Nullable {
+						errs = append(errs, ValidationError{table.Name, ri, col.Name, "non-null column is null"})
+					}
+					continue
+				}
+
+				if spec.Array {
+					raw, err := SplitArray(value)
+					if err != nil {
+						errs = append(errs, ValidationError{table.Name, ri, col.Name, err.Error()})
+						continue
+					}
+					converted := make([]any, len(raw))
+					for vi, item := range raw {
+						cv, reason := validateScalar(spec, item, tablesByName)
+						if reason != "" {
+							errs = append(errs, ValidationError{table.Name, ri, col.Name, reason})
+						}
+						converted[vi] = cv
+					}
+					row[ci] = converted
+					continue
+				}
+
+				cv, reason := validateScalar(spec, value, tablesByName)
+				if reason != "" {
+					errs = append(errs, ValidationError{table.Name, ri, col.Name, reason})
+				}
+				row[ci] = cv
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateScalar checks a single (non-array) value against spec and returns the
+// normalized value plus a non-empty reason string if validation failed.
+func validateScalar(spec TypeSpec, value any, tables map[string]*Table) (any, string) {
+	switch spec.Kind {
+	case "i8", "i16", "i32", "i64", "int":
+		n, ok := toInt64(value)
+		if !ok {
+			return value, fmt.Sprintf("value %v is not an integer", value)
+		}
+		bounds := intBounds[spec.Kind]
+		if n < bounds[0] || n > bounds[1] {
+			return value, fmt.Sprintf("value %d out of range for %s", n, spec.Kind)
+		}
+		return n, ""
+
+	case "u8", "u16", "u32", "u64", "uint":
+		n, ok := toInt64(value)
+		if !ok || n < 0 {
+			return value, fmt.Sprintf("value %v is not an unsigned integer", value)
+		}
+		if max, ok := uintBounds[spec.Kind]; ok && uint64(n) > max {
+			return value, fmt.Sprintf("value %d out of range for %s", n, spec.Kind)
+		}
+		return n, ""
+
+	case "f32", "f64", "float":
+		f, ok := toFloat64(value)
+		if !ok {
+			return value, fmt.Sprintf("value %v is not a number", value)
+		}
+		return f, ""
+
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return value, fmt.Sprintf("value %v is not a bool", value)
+		}
+		return value, ""
+
+	case "str", "text":
+		return fmt.Sprintf("%v", value), ""
+
+	case "date", "time", "datetime", "timestamp":
+		s := fmt.Sprintf("%v", value)
+		t, ok := parseTimestamp(s)
+		if !ok {
+			return value, fmt.Sprintf("value %q is not a valid %s", s, spec.Kind)
+		}
+		return t.Format(time.RFC3339), ""
+
+	case "decimal":
+		s := fmt.Sprintf("%v", value)
+		if len(spec.Params) < 2 {
+			return s, ""
+		}
+		precision, _ := strconv.Atoi(spec.Params[0])
+		scale, _ := strconv.Atoi(spec.Params[1])
+		if err := validateDecimal(s, precision, scale); err != nil {
+			return value, err.Error()
+		}
+		return s, ""
+
+	case "enum":
+		s := fmt.Sprintf("%v", value)
+		for _, allowed := range spec.Params {
+			if s == allowed {
+				return s, ""
+			}
+		}
+		return value, fmt.Sprintf("value %q is not one of %v", s, spec.Params)
+
+	case "ref":
+		return validateRef(value, spec, tables)
+
+	default:
+		return value, ""
+	}
+}
+
+func validateRef(value any, spec TypeSpec, tables map[string]*Table) (any, string) {
+	if len(spec.Params) < 2 {
+		return value, ""
+	}
+	refTable, refCol := spec.Params[0], spec.Params[1]
+
+	target, ok := tables[refTable]
+	if !ok {
+		return value, fmt.Sprintf("referenced table %q does not exist", refTable)
+	}
+
+	colIdx := -1
+	for i, c := range target.Columns {
+		if c.Name == refCol {
+			colIdx = i
+			break
+		}
+	}
+	if colIdx == -1 {
+		return value, fmt.Sprintf("referenced column %q does not exist in %q", refCol, refTable)
+	}
+
+	key := fmt.Sprintf("%v", value)
+	matches := 0
+	for _, r := range target.Rows {
+		if colIdx < len(r) && fmt.Sprintf("%v", r[colIdx]) == key {
+			matches++
+		}
+	}
+	if matches == 0 {
+		return value, fmt.Sprintf("value %v has no matching row in %s.%s", value, refTable, refCol)
+	}
+	if matches > 1 {
+		return value, fmt.Sprintf("referenced key %v is not unique in %s.%s", value, refTable, refCol)
+	}
+
+	return value, ""
+}
+
+func validateDecimal(s string, precision, scale int) error {
+	s = strings.TrimPrefix(s, "-")
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	if len(fracPart) > scale {
+		return fmt.Errorf("value exceeds scale %d", scale)
+	}
+
+	digits := len(strings.TrimLeft(intPart, "0")) + len(fracPart)
+	if digits == 0 {
+		digits = 1
+	}
+	if digits > precision {
+		return fmt.Errorf("value exceeds precision %d", precision)
+	}
+
+	return nil
+}
+
+// SplitArray parses a "[]"-typed field into its element tokens. Fields may be written as
+// a bare pipe-delimited list ("a|b|c") or bracket-wrapped ("[a|b|c]"); a bare
+// single-element array has no delimiter at all, so if it reads as a number or bool,
+// cleanField has already coerced it to int64, float64 or bool by the time Validate sees
+// it, and it is formatted back to text here. Exported so a
+// caller that never ran the document through Validate (e.g. an emitter) can still split
+// a raw, not-yet-[]any array field itself.
+func SplitArray(value any) ([]string, error) {
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprintf("%v", value)
+	}
+
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "[")
+	s = strings.TrimSuffix(s, "]")
+	if s == "" {
+		return []string{}, nil
+	}
+
+	parts := strings.Split(s, "|")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+
+	return parts, nil
+}
+
+func toInt64(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) {
+			return int64(v), true
+		}
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+func toFloat64(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, layout := range TimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestValidateRowArity(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "User",
+		Columns: []Column{{Name: "id", Type: "uint"}, {Name: "name", Type: "str"}},
+		Rows:    [][]any{{int64(1)}},
+	}}}
+
+	errs := Validate(doc)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateIntegerBounds(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "Test",
+		Columns: []Column{{Name: "age", Type: "i8"}},
+		Rows:    [][]any{{int64(200)}},
+	}}}
+
+	errs := Validate(doc)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+	if errs[0].Column != "age" {
+		t.Errorf("Expected error on column 'age', got '%s'", errs[0].Column)
+	}
+}
+
+func TestValidateNonNullable(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "User",
+		Columns: []Column{{Name: "name", Type: "str"}},
+		Rows:    [][]any{{nil}},
+	}}}
+
+	errs := Validate(doc)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateNullableAllowsNull(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "Article",
+		Columns: []Column{{Name: "content", Type: "str?"}},
+		Rows:    [][]any{{nil}},
+	}}}
+
+	errs := Validate(doc)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateEnumMembership(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "Task",
+		Columns: []Column{{Name: "status", Type: "enum(pending | in_progress | completed)"}},
+		Rows:    [][]any{{"cancelled"}},
+	}}}
+
+	errs := Validate(doc)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateDecimalPrecisionScale(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "Product",
+		Columns: []Column{{Name: "price", Type: "decimal(4,2)"}},
+		Rows:    [][]any{{"123.456"}},
+	}}}
+
+	errs := Validate(doc)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateRefTargetExists(t *testing.T) {
+	doc := &Document{Tables: []Table{
+		{
+			Name:    "Article",
+			Columns: []Column{{Name: "id", Type: "uint"}},
+			Rows:    [][]any{{int64(1)}},
+		},
+		{
+			Name:    "Comment",
+			Columns: []Column{{Name: "article_id", Type: "ref(Article.id)"}},
+			Rows:    [][]any{{int64(42)}},
+		},
+	}}
+
+	errs := Validate(doc)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateRefTargetMatches(t *testing.T) {
+	doc := &Document{Tables: []Table{
+		{
+			Name:    "Article",
+			Columns: []Column{{Name: "id", Type: "uint"}},
+			Rows:    [][]any{{int64(1)}},
+		},
+		{
+			Name:    "Comment",
+			Columns: []Column{{Name: "article_id", Type: "ref(Article.id)"}},
+			Rows:    [][]any{{int64(1)}},
+		},
+	}}
+
+	errs := Validate(doc)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateDateCanonicalization(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "Event",
+		Columns: []Column{{Name: "happened_at", Type: "date"}},
+		Rows:    [][]any{{"2024-01-15"}},
+	}}}
+
+	errs := Validate(doc)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %d: %v", len(errs), errs)
+	}
+	if doc.Tables[0].Rows[0][0] != "2024-01-15T00:00:00Z" {
+		t.Errorf("Expected canonical RFC3339 date, got %v", doc.Tables[0].Rows[0][0])
+	}
+}
+
+func TestValidateStrColumnPreservesQuotedNumericLiteral(t *testing.T) {
+	doc, err := ParseString("#! Product\n#@ sku:str\n\"3.0\"\n")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	errs := Validate(doc)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %d: %v", len(errs), errs)
+	}
+	if doc.Tables[0].Rows[0][0] != "3.0" {
+		t.Errorf("Expected quoted field to survive as the string \"3.0\", got %#v", doc.Tables[0].Rows[0][0])
+	}
+}
+
+func TestValidateStrColumnPreservesQuotedIntegerLiteral(t *testing.T) {
+	doc, err := ParseString("#! User\n#@ id:str\n\"42\"\n")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	errs := Validate(doc)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %d: %v", len(errs), errs)
+	}
+	if doc.Tables[0].Rows[0][0] != "42" {
+		t.Errorf("Expected quoted field to survive as the string \"42\", got %#v", doc.Tables[0].Rows[0][0])
+	}
+}
+
+func TestValidateArrayField(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "Test",
+		Columns: []Column{{Name: "tags", Type: "str[]"}},
+		Rows:    [][]any{{"[one|two|three]"}},
+	}}}
+
+	errs := Validate(doc)
+	if len(errs) != 0 {
+		t.Fatalf("Expected no errors, got %d: %v", len(errs), errs)
+	}
+	tags, ok := doc.Tables[0].Rows[0][0].([]any)
+	if !ok || len(tags) != 3 {
+		t.Fatalf("Expected 3-element array, got %v", doc.Tables[0].Rows[0][0])
+	}
+	if tags[1] != "two" {
+		t.Errorf("Expected 'two', got %v", tags[1])
+	}
+}
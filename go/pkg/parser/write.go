@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteOptions controls how WriteTL renders a Document back into 3TL text.
+type WriteOptions struct {
+	// Align pads every column to the width of its widest cell (including the header),
+	// producing human-readable aligned tables instead of minimal output.
+	Align bool
+}
+
+// WriteTL serializes doc as 3TL text, re-emitting `#!` table headers, `#@` schema lines
+// and data rows with correct quoting. Column order and canonical type spellings are
+// preserved from doc, so ParseString(WriteTL output) reproduces doc structurally. An
+// array value held as []any (as Validate leaves it) is written as "[a|b]" and reads
+// back as that string, not as []any.
+func WriteTL(w io.Writer, doc *Document, opts WriteOptions) error {
+	for i := range doc.Tables {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := writeTable(w, &doc.Tables[i], opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTable(w io.Writer, table *Table, opts WriteOptions) error {
+	if _, err := fmt.Fprintf(w, "#! %s\n", table.Name); err != nil {
+		return err
+	}
+
+	schemaCells := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		schemaCells[i] = col.Name + ":" + col.Type
+	}
+
+	rowCells := make([][]string, len(table.Rows))
+	for ri, row := range table.Rows {
+		cells := make([]string, len(row))
+		for ci, value := range row {
+			cells[ci] = formatField(value)
+		}
+		rowCells[ri] = cells
+	}
+
+	if opts.Align {
+		widths := make([]int, len(table.Columns))
+		for i, cell := range schemaCells {
+			widths[i] = len(cell)
+		}
+		for _, cells := range rowCells {
+			for i, cell := range cells {
+				if i < len(widths) && len(cell) > widths[i] {
+					widths[i] = len(cell)
+				}
+			}
+		}
+		padCells(schemaCells, widths)
+		for _, cells := range rowCells {
+			padCells(cells, widths)
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "#@ %s\n", strings.Join(schemaCells, ", ")); err != nil {
+		return err
+	}
+
+	for _, cells := range rowCells {
+		if _, err := fmt.Fprintf(w, "%s\n", strings.Join(cells, ", ")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func padCells(cells []string, widths []int) {
+	for i := range cells {
+		if i < len(widths) && len(cells[i]) < widths[i] {
+			cells[i] += strings.Repeat(" ", widths[i]-len(cells[i]))
+		}
+	}
+}
+
+func formatField(value any) string {
+	if value == nil {
+		return ""
+	}
+	s := fieldToString(value)
+	if needsQuoting(s) {
+		return quoteField(s)
+	}
+	if str, ok := value.(string); ok {
+		// A string that cleanField would read back as a bool or number keeps its quotes.
+		t := strings.TrimSpace(str)
+		if _, err := strconv.ParseFloat(t, 64); err == nil || strings.EqualFold(t, "true") || strings.EqualFold(t, "false") {
+			return quoteField(s)
+		}
+	}
+	return s
+}
+
+func fieldToString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eIN") {
+			// A whole-valued float keeps a ".0" so it is not read back as an int64.
+			s += ".0"
+		}
+		return s
+	case []any:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = fieldToString(e)
+		}
+		return "[" + strings.Join(parts, "|") + "]"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// needsQuoting reports whether an unquoted field would round-trip incorrectly and must
+// instead be wrapped in double quotes.
+func needsQuoting(s string) bool {
+	if s == "" {
+		// A bare empty field parses back as nil, not "": only a quoted "" round-trips
+		// to the empty string.
+		return true
+	}
+	if strings.ContainsAny(s, ",\n#\"") {
+		return true
+	}
+	if strings.ContainsAny(s, " \t") {
+		// Any internal whitespace needs quoting too, not just leading/trailing: the
+		// lexer elides whitespace between tokens, so an unquoted "a b" would re-parse
+		// as "ab".
+		return true
+	}
+	if strings.EqualFold(s, "null") {
+		return true
+	}
+	return false
+}
+
+func quoteField(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
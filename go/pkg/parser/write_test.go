@@ -0,0 +1,220 @@
+package parser
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// roundTripFixtures mirrors the inputs used by the ParseString tests in parser_test.go,
+// so WriteTL is exercised against every shape the parser itself is tested against.
+var roundTripFixtures = []string{
+	`#! User
+#@ id:uint, name:str, email:str
+1, Alice, alice@example.com
+2, Bob, bob@example.com
+`,
+	`#! Article
+#@ id:uint, title:str, content:str?
+1, Hello, This is content
+2, World,
+`,
+	`#! Product
+#@ id:uint, price:decimal(10,2)
+1, 19.99
+`,
+	`#! Comment
+#@ id:uint, article_id:ref(Article.id)
+1, 42
+`,
+	`#! Task
+#@ id:uint, status:enum(pending | in_progress | completed)
+1, pending
+`,
+	`#! User
+#@ id:uint, name:str
+1, Alice
+
+#! Post
+#@ id:uint, user_id:ref(User.id), title:str
+1, 1, My First Post
+`,
+	`#! Article
+#@ id:uint, title:str, content:str
+1, "Hello, World", "This is test"
+2, Normal, "With ""quotes"" inside"
+`,
+	`#! Note
+#@ id:uint, body:str
+1, ""
+`,
+	`#! Test
+#@ id:uint, tags:str[]
+1, tag1
+`,
+	`# This is a comment
+#! User
+#@ id:uint, name:str
+1, Alice
+`,
+	`#! Test
+#@ id:UINT, name:STR, active:BOOL
+1, Alice, true
+`,
+	`#! Café
+#@ id:uint, nombre:str
+1, José
+`,
+}
+
+func TestWriteTLRoundTrip(t *testing.T) {
+	for i, fixture := range roundTripFixtures {
+		doc, err := ParseString(fixture)
+		if err != nil {
+			t.Fatalf("fixture %d: Parse error: %v", i, err)
+		}
+
+		var buf bytes.Buffer
+		if err := WriteTL(&buf, doc, WriteOptions{}); err != nil {
+			t.Fatalf("fixture %d: WriteTL error: %v", i, err)
+		}
+
+		reparsed, err := ParseString(buf.String())
+		if err != nil {
+			t.Fatalf("fixture %d: re-parse error: %v\noutput:\n%s", i, err, buf.String())
+		}
+
+		if !reflect.DeepEqual(doc, reparsed) {
+			t.Errorf("fixture %d: round-trip mismatch\noriginal: %+v\nreparsed: %+v\noutput:\n%s", i, doc, reparsed, buf.String())
+		}
+	}
+}
+
+func TestWriteTLAlignment(t *testing.T) {
+	doc, err := ParseString(`#! User
+#@ id:uint, name:str
+1, Alice
+2, Bob
+`)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTL(&buf, doc, WriteOptions{Align: true}); err != nil {
+		t.Fatalf("WriteTL error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %d: %q", len(lines), buf.String())
+	}
+	if len(lines[2]) != len(lines[3]) {
+		t.Errorf("Expected aligned rows to have equal width, got %q and %q", lines[2], lines[3])
+	}
+}
+
+func TestWriteTLQuotesSpecialFields(t *testing.T) {
+	doc := &Document{Tables: []Table{{
+		Name:    "Test",
+		Columns: []Column{{Name: "id", Type: "uint"}, {Name: "note", Type: "str"}},
+		Rows:    [][]any{{int64(1), "has, comma"}, {int64(2), " leading space"}},
+	}}}
+
+	var buf bytes.Buffer
+	if err := WriteTL(&buf, doc, WriteOptions{}); err != nil {
+		t.Fatalf("WriteTL error: %v", err)
+	}
+
+	reparsed, err := ParseString(buf.String())
+	if err != nil {
+		t.Fatalf("re-parse error: %v\noutput:\n%s", err, buf.String())
+	}
+	if reparsed.Tables[0].Rows[0][1] != "has, comma" {
+		t.Errorf("Expected 'has, comma', got %v", reparsed.Tables[0].Rows[0][1])
+	}
+	if reparsed.Tables[0].Rows[1][1] != " leading space" {
+		t.Errorf("Expected ' leading space', got %v", reparsed.Tables[0].Rows[1][1])
+	}
+}
+
+func TestParseJSONRoundTrip(t *testing.T) {
+	for i, fixture := range roundTripFixtures {
+		doc, err := ParseString(fixture)
+		if err != nil {
+			t.Fatalf("fixture %d: Parse error: %v", i, err)
+		}
+
+		jsonStr, err := ToJSON(doc, false)
+		if err != nil {
+			t.Fatalf("fixture %d: ToJSON error: %v", i, err)
+		}
+
+		reparsed, err := ParseJSON(strings.NewReader(jsonStr))
+		if err != nil {
+			t.Fatalf("fixture %d: ParseJSON error: %v", i, err)
+		}
+
+		if !reflect.DeepEqual(doc, reparsed) {
+			t.Errorf("fixture %d: JSON round-trip mismatch\noriginal: %+v\nreparsed: %+v", i, doc, reparsed)
+		}
+	}
+}
+
+func TestMergeAppendRows(t *testing.T) {
+	base, _ := ParseString("#! User\n#@ id:uint, name:str\n1, Alice\n")
+	extra, _ := ParseString("#! User\n#@ id:uint, name:str\n2, Bob\n")
+
+	if err := base.Merge(extra, AppendRows()); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if len(base.Tables[0].Rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(base.Tables[0].Rows))
+	}
+}
+
+func TestMergeReplaceByKey(t *testing.T) {
+	base, _ := ParseString("#! User\n#@ id:uint, name:str\n1, Alice\n2, Bob\n")
+	patch, _ := ParseString("#! User\n#@ id:uint, name:str\n2, Robert\n3, Carol\n")
+
+	if err := base.Merge(patch, ReplaceByKey("id")); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if len(base.Tables[0].Rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(base.Tables[0].Rows))
+	}
+	if base.Tables[0].Rows[1][1] != "Robert" {
+		t.Errorf("Expected row for id=2 to be replaced with 'Robert', got %v", base.Tables[0].Rows[1][1])
+	}
+}
+
+func TestMergeUnionSchema(t *testing.T) {
+	base, _ := ParseString("#! User\n#@ id:uint, name:str\n1, Alice\n")
+	extra, _ := ParseString("#! User\n#@ id:uint, email:str\n2, bob@example.com\n")
+
+	if err := base.Merge(extra, UnionSchema()); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if len(base.Tables[0].Columns) != 3 {
+		t.Fatalf("Expected 3 columns, got %d", len(base.Tables[0].Columns))
+	}
+	if base.Tables[0].Rows[0][2] != nil {
+		t.Errorf("Expected backfilled nil for existing row, got %v", base.Tables[0].Rows[0][2])
+	}
+	if base.Tables[0].Rows[1][1] != nil {
+		t.Errorf("Expected nil for column absent from incoming row, got %v", base.Tables[0].Rows[1][1])
+	}
+}
+
+func TestMergeAppendsNewTable(t *testing.T) {
+	base, _ := ParseString("#! User\n#@ id:uint, name:str\n1, Alice\n")
+	extra, _ := ParseString("#! Post\n#@ id:uint, title:str\n1, Hello\n")
+
+	if err := base.Merge(extra, AppendRows()); err != nil {
+		t.Fatalf("Merge error: %v", err)
+	}
+	if len(base.Tables) != 2 {
+		t.Fatalf("Expected 2 tables, got %d", len(base.Tables))
+	}
+}
@@ -0,0 +1,655 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokKind enumerates the lexical tokens shared by path expressions and the filter
+// expressions embedded inside `[?(...)]`.
+type tokKind int
+
+const (
+	tEOF tokKind = iota
+	tDollar
+	tDot
+	tDotDot
+	tAt
+	tIdent
+	tNumber
+	tString
+	tLBracket
+	tRBracket
+	tLParen
+	tRParen
+	tColon
+	tComma
+	tStar
+	tQuestion
+	tMinus
+	tNot
+	tAnd
+	tOr
+	tEq
+	tNe
+	tLt
+	tLe
+	tGt
+	tGe
+)
+
+type token struct {
+	kind tokKind
+	text string
+}
+
+// tokenizePath lexes an entire JSONPath-style expression, including any embedded filter
+// expressions, into a single flat token stream.
+func tokenizePath(expr string) ([]token, error) {
+	var toks []token
+	runes := []rune(expr)
+	i := 0
+
+	for i < len(runes) {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '$':
+			toks = append(toks, token{tDollar, "$"})
+			i++
+
+		case c == '.':
+			if i+1 < len(runes) && runes[i+1] == '.' {
+				toks = append(toks, token{tDotDot, ".."})
+				i += 2
+			} else {
+				toks = append(toks, token{tDot, "."})
+				i++
+			}
+
+		case c == '@':
+			toks = append(toks, token{tAt, "@"})
+			i++
+
+		case c == '[':
+			toks = append(toks, token{tLBracket, "["})
+			i++
+
+		case c == ']':
+			toks = append(toks, token{tRBracket, "]"})
+			i++
+
+		case c == '(':
+			toks = append(toks, token{tLParen, "("})
+			i++
+
+		case c == ')':
+			toks = append(toks, token{tRParen, ")"})
+			i++
+
+		case c == ':':
+			toks = append(toks, token{tColon, ":"})
+			i++
+
+		case c == ',':
+			toks = append(toks, token{tComma, ","})
+			i++
+
+		case c == '*':
+			toks = append(toks, token{tStar, "*"})
+			i++
+
+		case c == '?':
+			toks = append(toks, token{tQuestion, "?"})
+			i++
+
+		case c == '-':
+			toks = append(toks, token{tMinus, "-"})
+			i++
+
+		case c == '!':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				toks = append(toks, token{tNe, "!="})
+				i += 2
+			} else {
+				toks = append(toks, token{tNot, "!"})
+				i++
+			}
+
+		case c == '=':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				toks = append(toks, token{tEq, "=="})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '=' at offset %d (did you mean '==')", i)
+			}
+
+		case c == '<':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				toks = append(toks, token{tLe, "<="})
+				i += 2
+			} else {
+				toks = append(toks, token{tLt, "<"})
+				i++
+			}
+
+		case c == '>':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				toks = append(toks, token{tGe, ">="})
+				i += 2
+			} else {
+				toks = append(toks, token{tGt, ">"})
+				i++
+			}
+
+		case c == '&':
+			if i+1 < len(runes) && runes[i+1] == '&' {
+				toks = append(toks, token{tAnd, "&&"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '&' at offset %d (did you mean '&&')", i)
+			}
+
+		case c == '|':
+			if i+1 < len(runes) && runes[i+1] == '|' {
+				toks = append(toks, token{tOr, "||"})
+				i += 2
+			} else {
+				return nil, fmt.Errorf("unexpected '|' at offset %d (did you mean '||')", i)
+			}
+
+		case c == '"' || c == '\'':
+			quote := c
+			j := i + 1
+			for j < len(runes) && runes[j] != quote {
+				j++
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string literal at offset %d", i)
+			}
+			toks = append(toks, token{tString, string(runes[i+1 : j])})
+			i = j + 1
+
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{tNumber, string(runes[i:j])})
+			i = j
+
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			toks = append(toks, token{tIdent, string(runes[i:j])})
+			i = j
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+		}
+	}
+
+	return toks, nil
+}
+
+// pathParser is a simple recursive-descent parser over a token stream shared by both
+// the path grammar and the filter expression grammar.
+type pathParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *pathParser) peek() token {
+	if p.pos >= len(p.toks) {
+		return token{kind: tEOF}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *pathParser) next() token {
+	tok := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *pathParser) expect(kind tokKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, fmt.Errorf("expected %s, got %q", what, tok.text)
+	}
+	return tok, nil
+}
+
+func (p *pathParser) parse() ([]pathNode, error) {
+	if _, err := p.expect(tDollar, "'$'"); err != nil {
+		return nil, err
+	}
+
+	var nodes []pathNode
+	for p.peek().kind != tEOF {
+		switch p.peek().kind {
+		case tDotDot:
+			p.next()
+			ident, err := p.expect(tIdent, "identifier after '..'")
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, pathNode{kind: nodeRecursive, name: ident.text})
+
+		case tDot:
+			p.next()
+			ident, err := p.expect(tIdent, "identifier after '.'")
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, pathNode{kind: nodeChild, name: ident.text})
+
+		case tLBracket:
+			node, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+
+		default:
+			return nil, fmt.Errorf("unexpected token %q", p.peek().text)
+		}
+	}
+
+	return nodes, nil
+}
+
+func (p *pathParser) parseBracket() (pathNode, error) {
+	p.next() // consume '['
+
+	switch p.peek().kind {
+	case tQuestion:
+		p.next()
+		if _, err := p.expect(tLParen, "'(' after '?'"); err != nil {
+			return pathNode{}, err
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return pathNode{}, err
+		}
+		if _, err := p.expect(tRParen, "')' to close filter"); err != nil {
+			return pathNode{}, err
+		}
+		if _, err := p.expect(tRBracket, "']' to close filter"); err != nil {
+			return pathNode{}, err
+		}
+		return pathNode{kind: nodeFilter, filter: &filterExpr{root: expr}}, nil
+
+	case tStar:
+		p.next()
+		if _, err := p.expect(tRBracket, "']' after '*'"); err != nil {
+			return pathNode{}, err
+		}
+		return pathNode{kind: nodeWildcard}, nil
+
+	case tString:
+		tok := p.next()
+		if _, err := p.expect(tRBracket, "']' after field name"); err != nil {
+			return pathNode{}, err
+		}
+		return pathNode{kind: nodeChild, name: tok.text}, nil
+
+	default:
+		return p.parseIndexOrSlice()
+	}
+}
+
+func (p *pathParser) parseIndexOrSlice() (pathNode, error) {
+	first, hasFirst, err := p.parseOptionalSignedInt()
+	if err != nil {
+		return pathNode{}, err
+	}
+
+	if p.peek().kind != tColon {
+		if !hasFirst {
+			return pathNode{}, fmt.Errorf("expected index, slice or '*' inside '[]'")
+		}
+		if _, err := p.expect(tRBracket, "']' after index"); err != nil {
+			return pathNode{}, err
+		}
+		return pathNode{kind: nodeIndex, index: first}, nil
+	}
+
+	p.next() // consume ':'
+	second, hasSecond, err := p.parseOptionalSignedInt()
+	if err != nil {
+		return pathNode{}, err
+	}
+
+	step, hasStep := 0, false
+	if p.peek().kind == tColon {
+		p.next()
+		step, hasStep, err = p.parseOptionalSignedInt()
+		if err != nil {
+			return pathNode{}, err
+		}
+	}
+
+	if _, err := p.expect(tRBracket, "']' to close slice"); err != nil {
+		return pathNode{}, err
+	}
+
+	return pathNode{
+		kind:     nodeSlice,
+		slice:    [3]int{first, second, step},
+		sliceSet: [3]bool{hasFirst, hasSecond, hasStep},
+	}, nil
+}
+
+func (p *pathParser) parseOptionalSignedInt() (int, bool, error) {
+	neg := false
+	if p.peek().kind == tMinus {
+		p.next()
+		neg = true
+	}
+	if p.peek().kind != tNumber {
+		if neg {
+			return 0, false, fmt.Errorf("expected number after '-'")
+		}
+		return 0, false, nil
+	}
+	tok := p.next()
+	n, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid integer %q", tok.text)
+	}
+	if neg {
+		n = -n
+	}
+	return n, true, nil
+}
+
+// parseSignedNumber parses the tMinus/tNumber tokens that make up a filter-expression
+// numeric literal, which (unlike an index or slice bound) may be a decimal such as
+// "19.99", so it parses as float64 rather than routing through parseOptionalSignedInt.
+func (p *pathParser) parseSignedNumber() (float64, error) {
+	neg := false
+	if p.peek().kind == tMinus {
+		p.next()
+		neg = true
+	}
+	if p.peek().kind != tNumber {
+		return 0, fmt.Errorf("expected number")
+	}
+	tok := p.next()
+	n, err := strconv.ParseFloat(tok.text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", tok.text)
+	}
+	if neg {
+		n = -n
+	}
+	return n, nil
+}
+
+// filterExpr wraps the parsed boolean expression tree used inside `[?(...)]`.
+type filterExpr struct {
+	root exprNode
+}
+
+func (f *filterExpr) eval(ctx map[string]any) (bool, error) {
+	return f.root.evalBool(ctx)
+}
+
+type exprNode interface {
+	evalBool(ctx map[string]any) (bool, error)
+}
+
+type andNode struct{ left, right exprNode }
+
+func (n *andNode) evalBool(ctx map[string]any) (bool, error) {
+	l, err := n.left.evalBool(ctx)
+	if err != nil || !l {
+		return false, err
+	}
+	return n.right.evalBool(ctx)
+}
+
+type orNode struct{ left, right exprNode }
+
+func (n *orNode) evalBool(ctx map[string]any) (bool, error) {
+	l, err := n.left.evalBool(ctx)
+	if err != nil {
+		return false, err
+	}
+	if l {
+		return true, nil
+	}
+	return n.right.evalBool(ctx)
+}
+
+type notNode struct{ x exprNode }
+
+func (n *notNode) evalBool(ctx map[string]any) (bool, error) {
+	v, err := n.x.evalBool(ctx)
+	return !v, err
+}
+
+type operand struct {
+	isField bool
+	field   string
+	lit     any
+}
+
+func (o operand) resolve(ctx map[string]any) any {
+	if o.isField {
+		return ctx[o.field]
+	}
+	return o.lit
+}
+
+type cmpNode struct {
+	op          tokKind
+	left, right operand
+}
+
+func (n *cmpNode) evalBool(ctx map[string]any) (bool, error) {
+	return compareValues(n.left.resolve(ctx), n.op, n.right.resolve(ctx))
+}
+
+type truthyNode struct{ operand operand }
+
+func (n *truthyNode) evalBool(ctx map[string]any) (bool, error) {
+	switch v := n.operand.resolve(ctx).(type) {
+	case bool:
+		return v, nil
+	default:
+		return v != nil, nil
+	}
+}
+
+func (p *pathParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *pathParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *pathParser) parseUnary() (exprNode, error) {
+	if p.peek().kind == tNot {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *pathParser) parsePrimary() (exprNode, error) {
+	if p.peek().kind == tLParen {
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tRParen, "')' to close group"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	return p.parseComparison()
+}
+
+var cmpOps = map[tokKind]bool{tEq: true, tNe: true, tLt: true, tLe: true, tGt: true, tGe: true}
+
+func (p *pathParser) parseComparison() (exprNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if !cmpOps[p.peek().kind] {
+		return &truthyNode{left}, nil
+	}
+
+	op := p.next().kind
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &cmpNode{op: op, left: left, right: right}, nil
+}
+
+func (p *pathParser) parseOperand() (operand, error) {
+	switch p.peek().kind {
+	case tAt:
+		p.next()
+		if p.peek().kind == tDot {
+			p.next()
+			ident, err := p.expect(tIdent, "field name after '@.'")
+			if err != nil {
+				return operand{}, err
+			}
+			return operand{isField: true, field: ident.text}, nil
+		}
+		return operand{isField: true, field: ""}, nil
+
+	case tString:
+		tok := p.next()
+		return operand{lit: tok.text}, nil
+
+	case tMinus, tNumber:
+		n, err := p.parseSignedNumber()
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{lit: n}, nil
+
+	case tIdent:
+		tok := p.next()
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return operand{lit: true}, nil
+		case "false":
+			return operand{lit: false}, nil
+		}
+		return operand{}, fmt.Errorf("unexpected identifier %q in filter expression", tok.text)
+
+	default:
+		return operand{}, fmt.Errorf("unexpected token %q in filter expression", p.peek().text)
+	}
+}
+
+// compareValues evaluates a comparison between two resolved operand values, coercing
+// numeric types (int64/float64/numeric strings) so that e.g. @.age (an int64 from the
+// parser) compares correctly against a bare numeric literal.
+func compareValues(l any, op tokKind, r any) (bool, error) {
+	if lf, lok := asFloat(l); lok {
+		if rf, rok := asFloat(r); rok {
+			return compareOrdered(lf, rf, op)
+		}
+	}
+
+	if lb, lok := l.(bool); lok {
+		if rb, rok := r.(bool); rok {
+			switch op {
+			case tEq:
+				return lb == rb, nil
+			case tNe:
+				return lb != rb, nil
+			}
+			return false, fmt.Errorf("operator not supported for booleans")
+		}
+	}
+
+	ls, rs := fmt.Sprintf("%v", l), fmt.Sprintf("%v", r)
+	return compareOrdered(ls, rs, op)
+}
+
+func asFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case string:
+		if f, err := strconv.ParseFloat(n, 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+type ordered interface{ ~float64 | ~string }
+
+func compareOrdered[T ordered](l, r T, op tokKind) (bool, error) {
+	switch op {
+	case tEq:
+		return l == r, nil
+	case tNe:
+		return l != r, nil
+	case tLt:
+		return l < r, nil
+	case tLe:
+		return l <= r, nil
+	case tGt:
+		return l > r, nil
+	case tGe:
+		return l >= r, nil
+	default:
+		return false, fmt.Errorf("unsupported comparison operator")
+	}
+}
@@ -0,0 +1,325 @@
+// Package query implements a JSONPath-style query language over parsed 3TL documents,
+// so a *parser.Document can be consumed by path expressions instead of only as a JSON
+// dump. It understands the shape of a Document (tables, columns, rows) directly: inside
+// a row context, filter expressions and child accessors resolve fields by column name
+// rather than positional index.
+package query
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+// Result is a single value matched by a Query, along with the path that reached it.
+type Result struct {
+	Value any
+	Path  string
+}
+
+// Query is a compiled path expression ready to be evaluated against a Document.
+type Query struct {
+	nodes []pathNode
+}
+
+type nodeKind int
+
+const (
+	nodeChild nodeKind = iota
+	nodeRecursive
+	nodeIndex
+	nodeSlice
+	nodeWildcard
+	nodeFilter
+)
+
+type pathNode struct {
+	kind     nodeKind
+	name     string
+	index    int
+	slice    [3]int
+	sliceSet [3]bool
+	filter   *filterExpr
+}
+
+// frame is an intermediate query result carried between path segments. columns holds
+// the schema of the nearest enclosing table, used to resolve field names against rows
+// (which are plain []any with no field names of their own).
+type frame struct {
+	value   any
+	path    string
+	columns []parser.Column
+}
+
+// Compile parses a JSONPath-style expression into a Query. Supported syntax:
+//
+//	$                  root
+//	.child / ['child'] child access
+//	..child            recursive descent
+//	[n]                index
+//	[start:end:step]   slice (any part may be omitted)
+//	[*]                wildcard
+//	[?(<expr>)]         filter, where <expr> combines comparisons (==, !=, <, <=, >, >=)
+//	                    with && || ! over @ (the current element) and @.field operands
+func Compile(expr string) (*Query, error) {
+	toks, err := tokenizePath(expr)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	p := &pathParser{toks: toks}
+	nodes, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return &Query{nodes: nodes}, nil
+}
+
+// Eval runs the compiled query against doc and returns every matching value together
+// with a JSONPath-style path back into the document, e.g. "$.tables[0].rows[1][0]".
+func (q *Query) Eval(doc *parser.Document) ([]Result, error) {
+	frames := []frame{{value: doc, path: "$"}}
+
+	for _, n := range q.nodes {
+		var next []frame
+		for _, f := range frames {
+			out, err := applyNode(n, f)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		frames = next
+	}
+
+	results := make([]Result, len(frames))
+	for i, f := range frames {
+		results[i] = Result{Value: f.value, Path: f.path}
+	}
+
+	return results, nil
+}
+
+func applyNode(n pathNode, f frame) ([]frame, error) {
+	switch n.kind {
+	case nodeChild:
+		return childAccess(f, n.name)
+	case nodeRecursive:
+		return recursiveAccess(f, n.name), nil
+	case nodeWildcard:
+		return wildcardAccess(f)
+	case nodeIndex:
+		return indexAccess(f, n.index)
+	case nodeSlice:
+		return sliceAccess(f, n.slice, n.sliceSet)
+	case nodeFilter:
+		return filterAccess(f, n.filter)
+	default:
+		return nil, fmt.Errorf("unsupported path node")
+	}
+}
+
+func childAccess(f frame, name string) ([]frame, error) {
+	switch v := f.value.(type) {
+	case *parser.Document:
+		if name == "tables" {
+			return []frame{{value: v.Tables, path: f.path + ".tables"}}, nil
+		}
+		return nil, fmt.Errorf("unknown field %q on document", name)
+
+	case parser.Table:
+		switch name {
+		case "name":
+			return []frame{{value: v.Name, path: f.path + ".name"}}, nil
+		case "columns":
+			return []frame{{value: v.Columns, path: f.path + ".columns"}}, nil
+		case "rows":
+			return []frame{{value: v.Rows, path: f.path + ".rows", columns: v.Columns}}, nil
+		}
+		return nil, fmt.Errorf("unknown field %q on table", name)
+
+	case parser.Column:
+		switch name {
+		case "name":
+			return []frame{{value: v.Name, path: f.path + ".name"}}, nil
+		case "type":
+			return []frame{{value: v.Type, path: f.path + ".type"}}, nil
+		}
+		return nil, fmt.Errorf("unknown field %q on column", name)
+
+	case []any:
+		idx := columnIndex(f.columns, name)
+		if idx == -1 {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		if idx >= len(v) {
+			return nil, fmt.Errorf("row has no value for column %q", name)
+		}
+		return []frame{{value: v[idx], path: fmt.Sprintf("%s.%s", f.path, name), columns: f.columns}}, nil
+
+	default:
+		return nil, fmt.Errorf("cannot access field %q on %T", name, f.value)
+	}
+}
+
+// recursiveAccess implements `..name`. For a 3TL document the only meaningful recursive
+// target is a table name, so `$..User` is shorthand for `$.tables[?(@.name=="User")].rows`.
+func recursiveAccess(f frame, name string) []frame {
+	var matches []frame
+
+	var walk func(value any, path string)
+	walk = func(value any, path string) {
+		switch v := value.(type) {
+		case *parser.Document:
+			for i, t := range v.Tables {
+				walk(t, fmt.Sprintf("%s.tables[%d]", path, i))
+			}
+		case parser.Table:
+			if v.Name == name {
+				matches = append(matches, frame{value: v.Rows, path: path + ".rows", columns: v.Columns})
+			}
+		}
+	}
+
+	walk(f.value, f.path)
+	return matches
+}
+
+func wildcardAccess(f frame) ([]frame, error) {
+	rv := reflect.ValueOf(f.value)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("wildcard on non-array value at %s", f.path)
+	}
+
+	frames := make([]frame, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		frames = append(frames, elementFrame(f, rv.Index(i).Interface(), i))
+	}
+	return frames, nil
+}
+
+func indexAccess(f frame, index int) ([]frame, error) {
+	rv := reflect.ValueOf(f.value)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("index on non-array value at %s", f.path)
+	}
+
+	i := index
+	if i < 0 {
+		i += rv.Len()
+	}
+	if i < 0 || i >= rv.Len() {
+		return nil, fmt.Errorf("index %d out of range at %s", index, f.path)
+	}
+
+	return []frame{elementFrame(f, rv.Index(i).Interface(), i)}, nil
+}
+
+func sliceAccess(f frame, bounds [3]int, set [3]bool) ([]frame, error) {
+	rv := reflect.ValueOf(f.value)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("slice on non-array value at %s", f.path)
+	}
+
+	length := rv.Len()
+	start, end, step := 0, length, 1
+	if set[2] {
+		step = bounds[2]
+	}
+	if step == 0 {
+		return nil, fmt.Errorf("slice step cannot be zero at %s", f.path)
+	}
+	if set[0] {
+		start = normalizeSliceIndex(bounds[0], length)
+	}
+	if set[1] {
+		end = normalizeSliceIndex(bounds[1], length)
+	}
+
+	var frames []frame
+	if step > 0 {
+		for i := start; i < end && i < length; i += step {
+			if i < 0 {
+				continue
+			}
+			frames = append(frames, elementFrame(f, rv.Index(i).Interface(), i))
+		}
+	} else {
+		for i := start; i > end && i >= 0; i += step {
+			if i >= length {
+				continue
+			}
+			frames = append(frames, elementFrame(f, rv.Index(i).Interface(), i))
+		}
+	}
+
+	return frames, nil
+}
+
+func normalizeSliceIndex(i, length int) int {
+	if i < 0 {
+		return i + length
+	}
+	return i
+}
+
+func filterAccess(f frame, filter *filterExpr) ([]frame, error) {
+	rv := reflect.ValueOf(f.value)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("filter on non-array value at %s", f.path)
+	}
+
+	var frames []frame
+	for i := 0; i < rv.Len(); i++ {
+		elem := rv.Index(i).Interface()
+		ctx := filterContext(elem, f.columns)
+
+		ok, err := filter.eval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("filter at %s[%d]: %w", f.path, i, err)
+		}
+		if ok {
+			frames = append(frames, elementFrame(f, elem, i))
+		}
+	}
+
+	return frames, nil
+}
+
+func elementFrame(f frame, elem any, index int) frame {
+	columns := f.columns
+	if t, ok := elem.(parser.Table); ok {
+		columns = t.Columns
+	}
+	return frame{value: elem, path: fmt.Sprintf("%s[%d]", f.path, index), columns: columns}
+}
+
+func filterContext(elem any, columns []parser.Column) map[string]any {
+	switch v := elem.(type) {
+	case []any:
+		ctx := make(map[string]any, len(columns))
+		for i, col := range columns {
+			if i < len(v) {
+				ctx[col.Name] = v[i]
+			}
+		}
+		return ctx
+	case parser.Table:
+		return map[string]any{"name": v.Name}
+	case parser.Column:
+		return map[string]any{"name": v.Name, "type": v.Type}
+	default:
+		return map[string]any{"": v}
+	}
+}
+
+func columnIndex(columns []parser.Column, name string) int {
+	for i, c := range columns {
+		if c.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,172 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/jiriknesl/3tl/pkg/parser"
+)
+
+func mustDoc(t *testing.T, input string) *parser.Document {
+	t.Helper()
+	doc, err := parser.ParseString(input)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	return doc
+}
+
+func TestQueryFilterTableByName(t *testing.T) {
+	doc := mustDoc(t, `#! User
+#@ id:uint, name:str, age:i32
+1, Alice, 42
+2, Bob, 17
+`)
+
+	q, err := Compile(`$.tables[?(@.name=="User")].rows[*][1]`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+
+	results, err := q.Eval(doc)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Value != "Alice" || results[1].Value != "Bob" {
+		t.Errorf("Expected [Alice Bob], got [%v %v]", results[0].Value, results[1].Value)
+	}
+}
+
+func TestQueryRecursiveFilterByColumn(t *testing.T) {
+	doc := mustDoc(t, `#! User
+#@ id:uint, name:str, age:i32
+1, Alice, 42
+2, Bob, 17
+`)
+
+	q, err := Compile(`$..User[?(@.age > 30)].name`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+
+	results, err := q.Eval(doc)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].Value != "Alice" {
+		t.Errorf("Expected 'Alice', got %v", results[0].Value)
+	}
+}
+
+func TestQueryWildcardIndex(t *testing.T) {
+	doc := mustDoc(t, `#! Product
+#@ id:uint, price:decimal(10,2)
+1, 9.99
+2, 19.99
+`)
+
+	q, err := Compile(`$.tables[0].rows[*][0]`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+
+	results, err := q.Eval(doc)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Path != "$.tables[0].rows[0][0]" {
+		t.Errorf("Expected path '$.tables[0].rows[0][0]', got %q", results[0].Path)
+	}
+}
+
+func TestQuerySliceWithStep(t *testing.T) {
+	doc := mustDoc(t, `#! Test
+#@ id:uint
+1
+2
+3
+4
+`)
+
+	q, err := Compile(`$.tables[0].rows[0:4:2][0]`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+
+	results, err := q.Eval(doc)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Value != int64(1) || results[1].Value != int64(3) {
+		t.Errorf("Expected [1 3], got [%v %v]", results[0].Value, results[1].Value)
+	}
+}
+
+func TestQueryFilterDecimalLiteral(t *testing.T) {
+	doc := mustDoc(t, `#! Product
+#@ id:uint, price:decimal(10,2)
+1, 9.99
+2, 29.99
+`)
+
+	q, err := Compile(`$.tables[0].rows[?(@.price > 19.99)][0]`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+
+	results, err := q.Eval(doc)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if results[0].Value != int64(2) {
+		t.Errorf("Expected id 2, got %v", results[0].Value)
+	}
+}
+
+func TestQueryAndFilter(t *testing.T) {
+	doc := mustDoc(t, `#! User
+#@ id:uint, name:str, age:i32
+1, Alice, 42
+2, Bob, 17
+3, Carol, 55
+`)
+
+	q, err := Compile(`$.tables[0].rows[?(@.age > 18 && @.age < 50)][1]`)
+	if err != nil {
+		t.Fatalf("Compile error: %v", err)
+	}
+
+	results, err := q.Eval(doc)
+	if err != nil {
+		t.Fatalf("Eval error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Value != "Alice" {
+		t.Fatalf("Expected [Alice], got %v", results)
+	}
+}
+
+func TestQueryInvalidExpression(t *testing.T) {
+	if _, err := Compile(`tables[0]`); err == nil {
+		t.Error("Expected error for expression missing leading '$'")
+	}
+}